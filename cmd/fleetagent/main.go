@@ -12,6 +12,7 @@ import (
 
 	"github.com/rancher/fleet/modules/agent/pkg/agent"
 	"github.com/rancher/fleet/modules/agent/pkg/simulator"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/version"
 
 	command "github.com/rancher/wrangler-cli"
@@ -27,6 +28,10 @@ type FleetAgent struct {
 	AgentScope      string `usage:"An identifier used to scope the agent bundleID names, typically the same as namespace" env:"AGENT_SCOPE"`
 	Simulators      int    `usage:"Numbers of simulators to run"`
 	CheckinInterval string `usage:"How often to post cluster status" env:"CHECKIN_INTERVAL"`
+	LeaseDuration   string `usage:"How long standby agents wait before taking over the leader lease" env:"LEADER_ELECTION_LEASE_DURATION"`
+	RenewDeadline   string `usage:"How long the leader retries renewing the lease before giving it up" env:"LEADER_ELECTION_RENEW_DEADLINE"`
+	RetryPeriod     string `usage:"How often agents try to acquire or renew the leader lease" env:"LEADER_ELECTION_RETRY_PERIOD"`
+	MetricsAddr     string `usage:"Address to serve Prometheus metrics on, empty to disable" default:":8080" env:"METRICS_ADDR"`
 }
 
 func (a *FleetAgent) Run(cmd *cobra.Command, args []string) error {
@@ -46,12 +51,29 @@ func (a *FleetAgent) Run(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	for _, d := range []struct {
+		value string
+		dest  *time.Duration
+	}{
+		{a.LeaseDuration, &opts.LeaderElection.LeaseDuration},
+		{a.RenewDeadline, &opts.LeaderElection.RenewDeadline},
+		{a.RetryPeriod, &opts.LeaderElection.RetryPeriod},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if *d.dest, err = time.ParseDuration(d.value); err != nil {
+			return err
+		}
+	}
 	if a.Namespace == "" {
 		return fmt.Errorf("--namespace or env NAMESPACE is required to be set")
 	}
 	if a.Simulators > 0 {
 		return simulator.Simulate(cmd.Context(), a.Simulators, a.Kubeconfig, a.Namespace, "default", opts)
 	}
+	metrics.RegisterAgentMetrics()
+	metrics.Serve(cmd.Context(), a.MetricsAddr)
 	if err := agent.Start(cmd.Context(), a.Kubeconfig, a.Namespace, a.AgentScope, &opts); err != nil {
 		return err
 	}
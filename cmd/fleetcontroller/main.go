@@ -18,6 +18,7 @@ import (
 
 	"github.com/rancher/fleet/pkg/agent"
 	"github.com/rancher/fleet/pkg/fleetcontroller"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/version"
 
 	command "github.com/rancher/wrangler-cli"
@@ -33,6 +34,7 @@ type FleetManager struct {
 	Kubeconfig    string `usage:"Kubeconfig file"`
 	Namespace     string `usage:"namespace to watch" default:"cattle-fleet-system" env:"NAMESPACE"`
 	DisableGitops bool   `usage:"disable gitops components" name:"disable-gitops"`
+	MetricsAddr   string `usage:"Address to serve Prometheus metrics on, empty to disable" default:":8080" env:"METRICS_ADDR"`
 }
 
 func (f *FleetManager) Run(cmd *cobra.Command, args []string) error {
@@ -41,6 +43,8 @@ func (f *FleetManager) Run(cmd *cobra.Command, args []string) error {
 		log.Println(http.ListenAndServe("localhost:6060", nil)) // nolint:gosec // Debugging only
 	}()
 	debugConfig.MustSetupDebug()
+	metrics.RegisterControllerMetrics()
+	metrics.Serve(cmd.Context(), f.MetricsAddr)
 	if err := fleetcontroller.Start(cmd.Context(), f.Namespace, f.Kubeconfig, f.DisableGitops); err != nil {
 		return err
 	}
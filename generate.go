@@ -1,5 +1,6 @@
 //go:generate go run pkg/codegen/cleanup/main.go
 //go:generate go run pkg/codegen/main.go
 //go:generate go run ./pkg/codegen crds ./charts/fleet-crd/templates/crds.yaml
+//go:generate go run ./pkg/codegen fleetyaml-schema ./schemas/fleet.yaml.json
 
 package main
@@ -26,6 +26,7 @@ type Options struct {
 	DefaultNamespace string
 	ClusterID        string
 	NoLeaderElect    bool
+	LeaderElection   controllers.LeaderElectionOptions
 	CheckinInterval  time.Duration
 	StartAfter       <-chan struct{}
 }
@@ -80,6 +81,7 @@ func Start(ctx context.Context, kubeConfig, namespace, agentScope string, opts *
 
 	return controllers.Register(ctx,
 		!opts.NoLeaderElect,
+		opts.LeaderElection,
 		fleetNamespace,
 		namespace,
 		opts.DefaultNamespace,
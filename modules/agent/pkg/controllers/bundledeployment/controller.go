@@ -15,17 +15,21 @@ import (
 	"github.com/rancher/fleet/modules/agent/pkg/trigger"
 	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/durations"
+	"github.com/rancher/fleet/pkg/events"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/helmdeployer"
+	"github.com/rancher/fleet/pkg/metrics"
 
 	"github.com/rancher/wrangler/pkg/condition"
 	"github.com/rancher/wrangler/pkg/merr"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 )
 
 type handler struct {
@@ -37,12 +41,14 @@ type handler struct {
 	bdController  fleetcontrollers.BundleDeploymentController
 	restMapper    meta.RESTMapper
 	dynamic       dynamic.Interface
+	recorder      record.EventRecorder
 }
 
 func Register(ctx context.Context,
 	trigger *trigger.Trigger,
 	restMapper meta.RESTMapper,
 	dynamic dynamic.Interface,
+	recorder record.EventRecorder,
 	deployManager *deployer.Manager,
 	bdController fleetcontrollers.BundleDeploymentController) {
 
@@ -53,6 +59,7 @@ func Register(ctx context.Context,
 		bdController:  bdController,
 		restMapper:    restMapper,
 		dynamic:       dynamic,
+		recorder:      recorder,
 	}
 
 	fleetcontrollers.RegisterBundleDeploymentStatusHandler(ctx,
@@ -97,6 +104,9 @@ func (h *handler) Cleanup(key string, bd *fleet.BundleDeployment) (*fleet.Bundle
 
 func (h *handler) DeployBundle(bd *fleet.BundleDeployment, status fleet.BundleDeploymentStatus) (fleet.BundleDeploymentStatus, error) {
 	if err := h.checkDependency(bd); err != nil {
+		if condition.Cond(fleet.BundleDeploymentConditionDeployed).GetMessage(bd) != err.Error() {
+			h.recorder.Event(bd, corev1.EventTypeWarning, events.ReasonDependencyNotReady, err.Error())
+		}
 		return status, err
 	}
 
@@ -117,10 +127,19 @@ func (h *handler) DeployBundle(bd *fleet.BundleDeployment, status fleet.BundleDe
 			// current one is running properly.
 			newStatus.Release = ""
 			newStatus.AppliedDeploymentID = bd.Spec.DeploymentID
+			if bd.Status.AppliedDeploymentID != bd.Spec.DeploymentID {
+				h.recorder.Event(bd, corev1.EventTypeWarning, events.ReasonDeployFailed, err.Error())
+			}
 			return newStatus, nil
 		}
+		if condition.Cond(fleet.BundleDeploymentConditionDeployed).GetMessage(bd) != err.Error() {
+			h.recorder.Event(bd, corev1.EventTypeWarning, events.ReasonDeployFailed, err.Error())
+		}
 		return status, err
 	}
+	if bd.Status.AppliedDeploymentID != bd.Spec.DeploymentID {
+		h.recorder.Eventf(bd, corev1.EventTypeNormal, events.ReasonDeployed, "Deployed %s as release %s", bd.Spec.DeploymentID, release)
+	}
 	status.Release = release
 	status.AppliedDeploymentID = bd.Spec.DeploymentID
 
@@ -324,10 +343,12 @@ func (h *handler) MonitorBundle(bd *fleet.BundleDeployment, status fleet.BundleD
 
 	status.NonReadyStatus = deploymentStatus.NonReadyStatus
 	status.ModifiedStatus = deploymentStatus.ModifiedStatus
+	wasReady := status.Ready
 	status.Ready = deploymentStatus.Ready
 	status.NonModified = deploymentStatus.NonModified
 
 	readyError := readyError(status)
+	recordMonitorEvents(h.recorder, bd, status, wasReady, readyError)
 	condition.Cond(fleet.BundleDeploymentConditionReady).SetError(&status, "", readyError)
 	if len(status.ModifiedStatus) > 0 {
 		h.bdController.EnqueueAfter(bd.Namespace, bd.Name, durations.MonitorBundleDelay)
@@ -351,6 +372,26 @@ func (h *handler) MonitorBundle(bd *fleet.BundleDeployment, status fleet.BundleD
 	return status, nil
 }
 
+// recordMonitorEvents records events for drift of the deployed resources
+// and for changes of their readiness.
+func recordMonitorEvents(recorder record.EventRecorder, bd *fleet.BundleDeployment, status fleet.BundleDeploymentStatus, wasReady bool, readyError error) {
+	wasModified, modified := len(bd.Status.ModifiedStatus) > 0, len(status.ModifiedStatus) > 0
+	switch {
+	case modified && !wasModified:
+		metrics.DriftDetections.WithLabelValues(bd.Name).Inc()
+		recorder.Event(bd, corev1.EventTypeWarning, events.ReasonDriftDetected, status.ModifiedStatus[0].String())
+	case !modified && wasModified:
+		recorder.Event(bd, corev1.EventTypeNormal, events.ReasonDriftCorrected, "Deployed resources match the desired state")
+	}
+	if wasReady != status.Ready && status.NonModified {
+		if status.Ready {
+			recorder.Event(bd, corev1.EventTypeNormal, events.ReasonReady, "Deployed resources are ready")
+		} else {
+			recorder.Event(bd, corev1.EventTypeWarning, string(fleet.NotReady), readyError.Error())
+		}
+	}
+}
+
 func readyError(status fleet.BundleDeploymentStatus) error {
 	if status.Ready && status.NonModified {
 		return nil
@@ -0,0 +1,89 @@
+package bundledeployment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordMonitorEvents(t *testing.T) {
+	modified := []fleet.ModifiedStatus{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "app", Name: "cm", Create: true}}
+
+	tests := []struct {
+		name        string
+		oldModified []fleet.ModifiedStatus
+		status      fleet.BundleDeploymentStatus
+		wasReady    bool
+		expected    []string
+	}{
+		{
+			name:     "ready",
+			status:   fleet.BundleDeploymentStatus{Ready: true, NonModified: true},
+			wasReady: true,
+		},
+		{
+			name:     "drift detected",
+			status:   fleet.BundleDeploymentStatus{Ready: true, ModifiedStatus: modified},
+			wasReady: true,
+			expected: []string{"Warning DriftDetected configmap.v1 app/cm missing"},
+		},
+		{
+			name:        "still modified",
+			oldModified: modified,
+			status:      fleet.BundleDeploymentStatus{Ready: true, ModifiedStatus: modified},
+			wasReady:    true,
+		},
+		{
+			name:        "drift corrected",
+			oldModified: modified,
+			status:      fleet.BundleDeploymentStatus{Ready: true, NonModified: true},
+			wasReady:    true,
+			expected:    []string{"Normal DriftCorrected Deployed resources match the desired state"},
+		},
+		{
+			name:     "ready again",
+			status:   fleet.BundleDeploymentStatus{Ready: true, NonModified: true},
+			expected: []string{"Normal Ready Deployed resources are ready"},
+		},
+		{
+			name:     "not ready",
+			status:   fleet.BundleDeploymentStatus{NonModified: true},
+			wasReady: true,
+			expected: []string{"Warning NotReady not ready"},
+		},
+		{
+			// readiness of modified resources is reported by the drift
+			name:     "not ready and modified",
+			status:   fleet.BundleDeploymentStatus{ModifiedStatus: modified},
+			wasReady: true,
+			expected: []string{"Warning DriftDetected configmap.v1 app/cm missing"},
+		},
+	}
+
+	for _, test := range tests {
+		recorder := record.NewFakeRecorder(10)
+		bd := &fleet.BundleDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Status:     fleet.BundleDeploymentStatus{ModifiedStatus: test.oldModified},
+		}
+		var readyErr error
+		if !test.status.Ready {
+			readyErr = errors.New("not ready")
+		}
+		recordMonitorEvents(recorder, bd, test.status, test.wasReady, readyErr)
+		close(recorder.Events)
+
+		var events []string
+		for event := range recorder.Events {
+			events = append(events, event)
+		}
+		if !reflect.DeepEqual(events, test.expected) {
+			t.Errorf("%s: expected events %q, got %q", test.name, test.expected, events)
+		}
+	}
+}
@@ -23,10 +23,18 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// Leader identifies the agent replica holding the leader lease. It is empty
+// if leader election is disabled.
+type Leader struct {
+	Identity string
+	Acquired time.Time
+}
+
 type handler struct {
 	agentNamespace   string
 	clusterName      string
 	clusterNamespace string
+	leader           Leader
 	nodes            corecontrollers.NodeCache
 	clusters         fleetcontrollers.ClusterClient
 	reported         fleet.AgentStatus
@@ -37,6 +45,7 @@ func Register(ctx context.Context,
 	clusterNamespace string,
 	clusterName string,
 	checkinInterval time.Duration,
+	leader Leader,
 	nodes corecontrollers.NodeCache,
 	clusters fleetcontrollers.ClusterClient) {
 
@@ -44,6 +53,7 @@ func Register(ctx context.Context,
 		agentNamespace:   agentNamespace,
 		clusterName:      clusterName,
 		clusterNamespace: clusterNamespace,
+		leader:           leader,
 		nodes:            nodes,
 		clusters:         clusters,
 	}
@@ -81,6 +91,10 @@ func (h *handler) Update() error {
 		NonReadyNodes: len(nonReady),
 		ReadyNodes:    len(ready),
 	}
+	if h.leader.Identity != "" {
+		agentStatus.LeaderIdentity = h.leader.Identity
+		agentStatus.LastLeaderTransition = metav1.NewTime(h.leader.Acquired)
+	}
 
 	if len(ready) > 3 {
 		ready = ready[:3]
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type nodeCache struct {
+	corecontrollers.NodeCache
+	nodes []*corev1.Node
+}
+
+func (n nodeCache) List(selector labels.Selector) ([]*corev1.Node, error) {
+	return n.nodes, nil
+}
+
+// clusterClient records the status patches of the cluster.
+type clusterClient struct {
+	fleetcontrollers.ClusterClient
+	patches []fleet.Cluster
+}
+
+func (c *clusterClient) Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (*fleet.Cluster, error) {
+	cluster := fleet.Cluster{}
+	if err := json.Unmarshal(data, &cluster); err != nil {
+		return nil, err
+	}
+	c.patches = append(c.patches, cluster)
+	return &cluster, nil
+}
+
+func newHandler(clusters *clusterClient, leader Leader) *handler {
+	return &handler{
+		agentNamespace:   "cattle-fleet-system",
+		clusterName:      "local",
+		clusterNamespace: "fleet-local",
+		leader:           leader,
+		nodes: nodeCache{nodes: []*corev1.Node{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}}},
+		clusters: clusters,
+	}
+}
+
+func TestUpdateLeader(t *testing.T) {
+	clusters := &clusterClient{}
+	first := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := newHandler(clusters, Leader{Identity: "agent-a", Acquired: first}).Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the standby replica took over the lease
+	second := time.Now().Truncate(time.Second)
+	if err := newHandler(clusters, Leader{Identity: "agent-b", Acquired: second}).Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clusters.patches) != 2 {
+		t.Fatalf("expected two status patches, got %d", len(clusters.patches))
+	}
+	for i, expected := range []Leader{{"agent-a", first}, {"agent-b", second}} {
+		status := clusters.patches[i].Status.Agent
+		if status.LeaderIdentity != expected.Identity || !status.LastLeaderTransition.Time.Equal(expected.Acquired) {
+			t.Errorf("expected leader %s since %s, got %s since %s", expected.Identity, expected.Acquired, status.LeaderIdentity, status.LastLeaderTransition)
+		}
+		if status.ReadyNodes != 1 || status.Namespace != "cattle-fleet-system" {
+			t.Errorf("unexpected agent status %+v", status)
+		}
+	}
+}
+
+func TestUpdateWithoutLeaderElection(t *testing.T) {
+	clusters := &clusterClient{}
+	if err := newHandler(clusters, Leader{}).Update(); err != nil {
+		t.Fatal(err)
+	}
+	if status := clusters.patches[0].Status.Agent; status.LeaderIdentity != "" || !status.LastLeaderTransition.IsZero() {
+		t.Errorf("expected no leader, got %s since %s", status.LeaderIdentity, status.LastLeaderTransition)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/rancher/fleet/modules/agent/pkg/deployer"
 	"github.com/rancher/fleet/modules/agent/pkg/trigger"
 	"github.com/rancher/fleet/pkg/durations"
+	"github.com/rancher/fleet/pkg/events"
 	"github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/helmdeployer"
@@ -24,7 +25,6 @@ import (
 	batchcontrollers "github.com/rancher/wrangler/pkg/generated/controllers/batch/v1"
 	"github.com/rancher/wrangler/pkg/generated/controllers/core"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
-	"github.com/rancher/wrangler/pkg/leader"
 	"github.com/rancher/wrangler/pkg/ratelimit"
 	"github.com/rancher/wrangler/pkg/start"
 
@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 )
 
 type appContext struct {
@@ -43,6 +44,7 @@ type appContext struct {
 	Dynamic  dynamic.Interface
 	K8s      kubernetes.Interface
 	Apply    apply.Apply
+	Recorder record.EventRecorder
 	starters []start.Starter
 
 	ClusterNamespace string
@@ -75,7 +77,7 @@ func (a *appContext) start(ctx context.Context) error {
 	return start.All(ctx, 5, a.starters...)
 }
 
-func Register(ctx context.Context, leaderElect bool,
+func Register(ctx context.Context, leaderElect bool, leaderOpts LeaderElectionOptions,
 	fleetNamespace, agentNamespace, defaultNamespace, agentScope, clusterNamespace, clusterName string,
 	checkinInterval time.Duration,
 	fleetConfig *rest.Config, clientConfig clientcmd.ClientConfig,
@@ -103,6 +105,7 @@ func Register(ctx context.Context, leaderElect bool,
 		trigger.New(ctx, appCtx.restMapper, appCtx.Dynamic),
 		appCtx.restMapper,
 		appCtx.Dynamic,
+		appCtx.Recorder,
 		deployer.NewManager(
 			fleetNamespace,
 			defaultNamespace,
@@ -114,21 +117,33 @@ func Register(ctx context.Context, leaderElect bool,
 			appCtx.Apply),
 		appCtx.Fleet.BundleDeployment())
 
-	cluster.Register(ctx,
-		appCtx.AgentNamespace,
-		appCtx.ClusterNamespace,
-		appCtx.ClusterName,
-		checkinInterval,
-		appCtx.Core.Node().Cache(),
-		appCtx.Fleet.Cluster())
+	// Only the leader reports the cluster status, standby replicas wait
+	// for the lease.
+	registerCluster := func(ctx context.Context, leader cluster.Leader) {
+		cluster.Register(ctx,
+			appCtx.AgentNamespace,
+			appCtx.ClusterNamespace,
+			appCtx.ClusterName,
+			checkinInterval,
+			leader,
+			appCtx.Core.Node().Cache(),
+			appCtx.Fleet.Cluster())
+	}
 
 	if leaderElect {
-		leader.RunOrDie(ctx, agentNamespace, "fleet-agent-lock", appCtx.K8s, func(ctx context.Context) {
+		return runLeaderElection(ctx, agentNamespace, "fleet-agent-lock", appCtx.K8s, leaderOpts, func(ctx context.Context, identity string, acquired time.Time) {
+			registerCluster(ctx, cluster.Leader{
+				Identity: identity,
+				Acquired: acquired,
+			})
 			if err := appCtx.start(ctx); err != nil {
 				logrus.Fatal(err)
 			}
 		})
-	} else if startChan != nil {
+	}
+
+	registerCluster(ctx, cluster.Leader{})
+	if startChan != nil {
 		go func() {
 			<-startChan
 			logrus.Fatalf("failed to start: %v", appCtx.start(ctx))
@@ -197,6 +212,11 @@ func newContext(fleetNamespace, agentNamespace, clusterNamespace, clusterName st
 		return nil, err
 	}
 
+	fleetK8s, err := kubernetes.NewForConfig(fleetConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	localConfig = rest.CopyConfig(localConfig)
 	localConfig.RateLimiter = ratelimit.None
 
@@ -211,6 +231,7 @@ func newContext(fleetNamespace, agentNamespace, clusterNamespace, clusterName st
 		Fleet:            fleetv,
 		Core:             corev,
 		K8s:              k8s,
+		Recorder:         events.NewRecorder(fleetK8s, "fleet-agent"),
 		ClusterNamespace: clusterNamespace,
 		ClusterName:      clusterName,
 		AgentNamespace:   agentNamespace,
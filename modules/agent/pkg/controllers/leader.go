@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/pkg/durations"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderElectionOptions configures the lease used to elect the agent replica,
+// which deploys bundles and reports the cluster status.
+type LeaderElectionOptions struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// leaderCallback is called once the lease is acquired, with the identity of
+// the replica and the time it became leader.
+type leaderCallback func(ctx context.Context, identity string, acquired time.Time)
+
+// runLeaderElection blocks until the context is done. Standby replicas take
+// over once the lease of the leader expires, or immediately if the leader
+// releases it on shutdown. A leader losing its lease exits, so it can restart
+// as a standby.
+func runLeaderElection(ctx context.Context, namespace, name string, k8s kubernetes.Interface, opts LeaderElectionOptions, cb leaderCallback) error {
+	if opts.LeaseDuration == 0 {
+		opts.LeaseDuration = durations.AgentLeaderLeaseDuration
+	}
+	if opts.RenewDeadline == 0 {
+		opts.RenewDeadline = durations.AgentLeaderRenewDeadline
+	}
+	if opts.RetryPeriod == 0 {
+		opts.RetryPeriod = durations.AgentLeaderRetryPeriod
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock,
+		namespace,
+		name,
+		k8s.CoreV1(),
+		k8s.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity: identity,
+		})
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Waiting to acquire leader lease %s/%s as %s", namespace, name, identity)
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: opts.LeaseDuration,
+		RenewDeadline: opts.RenewDeadline,
+		RetryPeriod:   opts.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				logrus.Infof("Acquired leader lease %s/%s as %s", namespace, name, identity)
+				go cb(ctx, identity, time.Now())
+			},
+			OnStoppedLeading: func() {
+				if ctx.Err() != nil {
+					// shutting down, the lease was released
+					return
+				}
+				logrus.Fatalf("leaderelection lost for %s", name)
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					logrus.Infof("Agent %s is the leader, standing by", current)
+				}
+			},
+		},
+		ReleaseOnCancel: true,
+	})
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunLeaderElection(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	k8s := fake.NewSimpleClientset()
+	opts := LeaderElectionOptions{
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+
+	type leader struct {
+		identity string
+		acquired time.Time
+	}
+	leaders := make(chan leader, 1)
+	done := make(chan error)
+	start := time.Now()
+	go func() {
+		done <- runLeaderElection(ctx, "cattle-fleet-system", "fleet-agent-lock", k8s, opts, func(ctx context.Context, identity string, acquired time.Time) {
+			leaders <- leader{identity, acquired}
+		})
+	}()
+
+	select {
+	case l := <-leaders:
+		if l.identity != hostname || l.acquired.Before(start) {
+			t.Errorf("expected %s to become leader after %s, got %s at %s", hostname, start, l.identity, l.acquired)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the leader lease")
+	}
+
+	lease, err := k8s.CoordinationV1().Leases("cattle-fleet-system").Get(ctx, "fleet-agent-lock", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != hostname {
+		t.Errorf("expected the lease to be held by %s, got %v", hostname, lease.Spec.HolderIdentity)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// the lease is released on shutdown, so a standby takes over immediately
+	lease, err = k8s.CoordinationV1().Leases("cattle-fleet-system").Get(context.Background(), "fleet-agent-lock", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != "" {
+		t.Errorf("expected the lease to be released, held by %s", *lease.Spec.HolderIdentity)
+	}
+}
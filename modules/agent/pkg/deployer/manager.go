@@ -1,12 +1,15 @@
 package deployer
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/helmdeployer"
 	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/wrangler/pkg/apply"
 	"github.com/rancher/wrangler/pkg/kv"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
@@ -139,10 +142,12 @@ func (m *Manager) Deploy(bd *fleet.BundleDeployment) (string, error) {
 	}
 
 	manifest.Commit = bd.Labels["fleet.cattle.io/commit"]
+	start := time.Now()
 	resource, err := m.deployer.Deploy(bd.Name, manifest, bd.Spec.Options)
 	if err != nil {
 		return "", err
 	}
+	metrics.BundleDeployDuration.WithLabelValues(bd.Name).Observe(time.Since(start).Seconds())
 
 	return resource.ID, nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/rancher/fleet/pkg/durations"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/wrangler/pkg/objectset"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -130,6 +131,7 @@ func (t *Trigger) call(gvk schema.GroupVersionKind, key objectset.ObjectKey) {
 	defer t.RUnlock()
 
 	for _, f := range t.triggers[gvk][key] {
+		metrics.TriggerEvents.Inc()
 		f()
 	}
 }
@@ -148,6 +150,7 @@ func (t *Trigger) watch(gvk schema.GroupVersionKind, gvr schema.GroupVersionReso
 		}
 		go gvkWatcher.Start(t.ctx)
 		t.watches[gvk] = gvkWatcher
+		metrics.TriggerWatches.Set(float64(len(t.watches)))
 	}
 }
 
@@ -160,6 +163,7 @@ func (t *Trigger) unwatch(gvk schema.GroupVersionKind) {
 	if gvkWatcher.count <= 0 {
 		gvkWatcher.Stop()
 		delete(t.watches, gvk)
+		metrics.TriggerWatches.Set(float64(len(t.watches)))
 	}
 }
 
@@ -48,6 +48,10 @@ type Options struct {
 	Labels          map[string]string
 	SyncGeneration  int64
 	Auth            bundlereader.Auth
+	// Report is filled with the bundles read and pruned, if not nil
+	Report *Report
+	// Targets are appended to the bundles, unless a TargetsFile is given
+	Targets *fleet.BundleSpec
 }
 
 func globDirs(baseDir string) (result []string, err error) {
@@ -80,17 +84,86 @@ func Apply(ctx context.Context, client *client.Getter, repoName string, baseDirs
 
 	foundBundle := false
 	gitRepoBundlesMap := make(map[string]bool)
+	err := walkBundleDirs(baseDirs, func(i int, root, path string) error {
+		if i > 0 && root == path && opts.Output != nil {
+			if _, err := opts.Output.Write([]byte("\n---\n")); err != nil {
+				return err
+			}
+		}
+		if err := Dir(ctx, client, repoName, path, opts, gitRepoBundlesMap); errors.Is(err, ErrNoResources) {
+			logrus.Warnf("%s: %v", path, ErrNoResources)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		foundBundle = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == nil {
+		pruned, err := PruneBundlesNotFoundInRepo(client, repoName, gitRepoBundlesMap)
+		if opts.Report != nil {
+			opts.Report.Pruned = pruned
+		}
+		if err != nil {
+			return &Error{Code: ExitAPI, Err: err}
+		}
+	}
+
+	if !foundBundle {
+		return &Error{Code: ExitValidation, Err: fmt.Errorf("no resource found at the following paths to deploy: %v", baseDirs)}
+	}
+
+	return nil
+}
+
+// Bundles reads the bundles from the baseDirs like Apply does, but returns
+// them instead of creating them in the cluster.
+func Bundles(ctx context.Context, repoName string, baseDirs []string, opts *Options) ([]*fleet.Bundle, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	if len(baseDirs) == 0 {
+		baseDirs = []string{"."}
+	}
+
+	var bundles []*fleet.Bundle
+	err := walkBundleDirs(baseDirs, func(_ int, _, path string) error {
+		bundle, _, err := readBundle(ctx, createName(repoName, path), path, opts)
+		if err != nil {
+			return err
+		}
+		if len(bundle.Spec.Resources) == 0 {
+			logrus.Warnf("%s: %v", path, ErrNoResources)
+			return nil
+		}
+		bundles = append(bundles, bundle)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bundles) == 0 {
+		return nil, fmt.Errorf("no resource found at the following paths to deploy: %v", baseDirs)
+	}
+	return bundles, nil
+}
+
+// walkBundleDirs calls fn for every directory matched by the baseDirs globs,
+// and every subdirectory containing a fleet.yaml. i is the index of the glob
+// in baseDirs and root the matched directory.
+func walkBundleDirs(baseDirs []string, fn func(i int, root, path string) error) error {
 	for i, baseDir := range baseDirs {
 		matches, err := globDirs(baseDir)
 		if err != nil {
-			return fmt.Errorf("invalid path glob %s: %w", baseDir, err)
+			return &Error{Code: ExitValidation, Err: fmt.Errorf("invalid path glob %s: %w", baseDir, err)}
 		}
 		for _, baseDir := range matches {
-			if i > 0 && opts.Output != nil {
-				if _, err := opts.Output.Write([]byte("\n---\n")); err != nil {
-					return err
-				}
-			}
 			err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
 				// always consider the root valid
 				if baseDir != path {
@@ -101,58 +174,66 @@ func Apply(ctx context.Context, client *client.Getter, repoName string, baseDirs
 						return nil
 					}
 				}
-				if err := Dir(ctx, client, repoName, path, opts, gitRepoBundlesMap); err == ErrNoResources {
-					logrus.Warnf("%s: %v", path, err)
-					return nil
-				} else if err != nil {
-					return err
-				}
-				foundBundle = true
-
-				return nil
+				return fn(i, baseDir, path)
 			})
 			if err != nil {
 				return err
 			}
 		}
 	}
+	return nil
+}
 
-	if opts.Output == nil {
-		err := pruneBundlesNotFoundInRepo(client, repoName, gitRepoBundlesMap)
-		if err != nil {
-			return err
-		}
-	}
-
-	if !foundBundle {
-		return fmt.Errorf("no resource found at the following paths to deploy: %v", baseDirs)
-	}
+// BundleDirs returns the directories Apply reads bundles from, in order.
+func BundleDirs(baseDirs []string) ([]string, error) {
+	var dirs []string
+	err := walkBundleDirs(baseDirs, func(_ int, _, path string) error {
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
 
-	return nil
+// BundleName returns the name of the bundle Apply creates for the directory
+// path, unless the fleet.yaml sets a name.
+func BundleName(repoName, path string) string {
+	return createName(repoName, path)
 }
 
-// pruneBundlesNotFoundInRepo lists all bundles for this gitrepo and prunes those not found in the repo
-func pruneBundlesNotFoundInRepo(client *client.Getter, repoName string, gitRepoBundlesMap map[string]bool) error {
+// PruneBundlesNotFoundInRepo lists all bundles for this gitrepo and prunes
+// those not found in the repo. It returns the names of the pruned bundles.
+func PruneBundlesNotFoundInRepo(client *client.Getter, repoName string, gitRepoBundlesMap map[string]bool) ([]string, error) {
 	c, err := client.Get()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	filter := labels.Set(map[string]string{fleet.RepoLabel: repoName})
 	bundles, err := c.Fleet.Bundle().List(client.Namespace, metav1.ListOptions{LabelSelector: filter.AsSelector().String()})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var pruned []string
 	for _, bundle := range bundles.Items {
 		if ok := gitRepoBundlesMap[bundle.Name]; !ok {
 			logrus.Debugf("Bundle to be deleted since it is not found in gitrepo %v anymore %v %v", repoName, bundle.Namespace, bundle.Name)
 			err = c.Fleet.Bundle().Delete(bundle.Namespace, bundle.Name, nil)
 			if err != nil {
-				return err
+				return pruned, err
 			}
+			pruned = append(pruned, bundle.Name)
 		}
 	}
-	return err
+	return pruned, err
+}
+
+// Read reads the bundle and the image scans in the directory path, like
+// Apply does.
+func Read(ctx context.Context, repoName, path string, opts *Options) (*fleet.Bundle, []*fleet.ImageScan, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return readBundle(ctx, createName(repoName, path), path, opts)
 }
 
 // readBundle reads bundle data from a source and returns a bundle with the
@@ -171,6 +252,7 @@ func readBundle(ctx context.Context, name, baseDir string, opts *Options) (*flee
 		Labels:          opts.Labels,
 		ServiceAccount:  opts.ServiceAccount,
 		TargetsFile:     opts.TargetsFile,
+		Targets:         opts.Targets,
 		TargetNamespace: opts.TargetNamespace,
 		Paused:          opts.Paused,
 		SyncGeneration:  opts.SyncGeneration,
@@ -230,13 +312,22 @@ func Dir(ctx context.Context, client *client.Getter, name, baseDir string, opts
 	}
 	bundle, scans, err := readBundle(ctx, createName(name, baseDir), baseDir, opts)
 	if err != nil {
-		return err
+		return readError(baseDir, err)
 	}
 
 	def := bundle.DeepCopy()
 	def.Namespace = client.Namespace
 
+	var report *BundleReport
+	if opts.Report != nil {
+		opts.Report.Bundles = append(opts.Report.Bundles, newBundleReport(baseDir, def, scans))
+		report = &opts.Report.Bundles[len(opts.Report.Bundles)-1]
+	}
+
 	if len(def.Spec.Resources) == 0 {
+		if report != nil {
+			report.Skipped = ErrNoResources.Error()
+		}
 		return ErrNoResources
 	}
 	gitRepoBundlesMap[def.Name] = true
@@ -252,15 +343,18 @@ func Dir(ctx context.Context, client *client.Getter, name, baseDir string, opts
 	}
 
 	if opts.Output == nil {
-		err = save(client, def, scans...)
-	} else {
-		_, err = opts.Output.Write(b)
+		if err := Save(client, def, scans...); err != nil {
+			return &Error{Code: ExitAPI, Path: baseDir, Err: err}
+		}
+		return nil
 	}
 
+	_, err = opts.Output.Write(b)
 	return err
 }
 
-func save(client *client.Getter, bundle *fleet.Bundle, imageScans ...*fleet.ImageScan) error {
+// Save creates or updates the bundle and its image scans in the Fleet Manager.
+func Save(client *client.Getter, bundle *fleet.Bundle, imageScans ...*fleet.ImageScan) error {
 	c, err := client.Get()
 	if err != nil {
 		return err
@@ -287,6 +381,9 @@ func save(client *client.Getter, bundle *fleet.Bundle, imageScans ...*fleet.Imag
 	for _, scan := range imageScans {
 		scan.Namespace = client.Namespace
 		scan.Spec.GitRepoName = bundle.Labels[fleet.RepoLabel]
+		scan.Labels = mergeMap(scan.Labels, map[string]string{
+			"fleet.cattle.io/bundle-name": bundle.Name,
+		})
 		obj, err := c.Fleet.ImageScan().Get(scan.Namespace, scan.Name, metav1.GetOptions{})
 		if apierrors.IsNotFound(err) {
 			if _, err = c.Fleet.ImageScan().Create(scan); err != nil {
@@ -298,7 +395,7 @@ func save(client *client.Getter, bundle *fleet.Bundle, imageScans ...*fleet.Imag
 		} else {
 			obj.Spec = scan.Spec
 			obj.Annotations = mergeMap(obj.Annotations, bundle.Annotations)
-			obj.Labels = mergeMap(obj.Labels, bundle.Labels)
+			obj.Labels = mergeMap(obj.Labels, mergeMap(bundle.Labels, scan.Labels))
 			if _, err := c.Fleet.ImageScan().Update(obj); err != nil {
 				return err
 			}
@@ -0,0 +1,149 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlereader"
+	"github.com/rancher/fleet/pkg/manifest"
+)
+
+// Exit codes of 'fleet apply', so CI can tell the causes of a failure apart.
+// Other errors exit with 1.
+const (
+	// ExitValidation is used for invalid paths, fleet.yaml files or bundles
+	ExitValidation = 2
+	// ExitFetch is used if remote content, like a helm chart, couldn't be
+	// downloaded
+	ExitFetch = 3
+	// ExitAPI is used if a request to the Fleet Manager failed
+	ExitAPI = 4
+)
+
+var reasons = map[int]string{
+	ExitValidation: "validation",
+	ExitFetch:      "fetch",
+	ExitAPI:        "api",
+}
+
+// Error is an error of Apply with the exit code of its cause.
+type Error struct {
+	Code int
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for err, 1 unless it's an Error.
+func ExitCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return 1
+}
+
+// readError classifies an error reading the bundle in path.
+func readError(path string, err error) error {
+	var fetchErr *bundlereader.FetchError
+	if errors.As(err, &fetchErr) {
+		return &Error{Code: ExitFetch, Path: path, Err: err}
+	}
+	return &Error{Code: ExitValidation, Path: path, Err: err}
+}
+
+// Report describes what Apply did, for machine-readable output.
+type Report struct {
+	RepoName string         `json:"repoName"`
+	Bundles  []BundleReport `json:"bundles"`
+	// Pruned are the bundles of the repo which were deleted, as they are
+	// not found in the paths anymore
+	Pruned []string     `json:"pruned,omitempty"`
+	Error  *ErrorReport `json:"error,omitempty"`
+}
+
+// BundleReport describes a bundle read from a path.
+type BundleReport struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// Style is helm, kustomize, helm+kustomize or rawyaml
+	Style string `json:"style"`
+	// Resources is the number of files in the bundle and Size the length
+	// of their content, after compression
+	Resources  int      `json:"resources"`
+	Size       int      `json:"size"`
+	Compressed bool     `json:"compressed"`
+	ImageScans []string `json:"imageScans,omitempty"`
+	// Skipped is the reason the bundle was not applied
+	Skipped string `json:"skipped,omitempty"`
+}
+
+type ErrorReport struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// SetError adds err to the report.
+func (r *Report) SetError(err error) {
+	if err == nil {
+		return
+	}
+	report := &ErrorReport{
+		Code:    ExitCode(err),
+		Message: err.Error(),
+	}
+	report.Reason = reasons[report.Code]
+	var e *Error
+	if errors.As(err, &e) {
+		report.Path = e.Path
+		report.Message = e.Err.Error()
+	}
+	if report.Reason == "" {
+		report.Reason = "unknown"
+	}
+	r.Error = report
+}
+
+func newBundleReport(path string, bundle *fleet.Bundle, scans []*fleet.ImageScan) BundleReport {
+	report := BundleReport{
+		Path:      path,
+		Name:      bundle.Name,
+		Resources: len(bundle.Spec.Resources),
+	}
+	for _, resource := range bundle.Spec.Resources {
+		report.Size += len(resource.Content)
+		if resource.Encoding != "" {
+			report.Compressed = true
+		}
+	}
+	for _, scan := range scans {
+		report.ImageScans = append(report.ImageScans, scan.Name)
+	}
+
+	m, _ := manifest.New(bundle.Spec.Resources)
+	style := bundlereader.DetermineStyle(m, bundle.Spec.BundleDeploymentOptions)
+	switch {
+	case style.IsHelm() && style.IsKustomize():
+		report.Style = "helm+kustomize"
+	case style.IsHelm():
+		report.Style = "helm"
+	case style.IsKustomize():
+		report.Style = "kustomize"
+	default:
+		report.Style = "rawyaml"
+	}
+	return report
+}
@@ -0,0 +1,60 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlereader"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewBundleReport(t *testing.T) {
+	bundle := &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo-chart"},
+		Spec: fleet.BundleSpec{
+			Resources: []fleet.BundleResource{
+				{Name: "Chart.yaml", Content: "name: chart"},
+				{Name: "templates/cm.yaml", Content: "H4sI", Encoding: "base64+gz"},
+			},
+		},
+	}
+	scans := []*fleet.ImageScan{{ObjectMeta: metav1.ObjectMeta{Name: "scan"}}}
+
+	assert.Equal(t, BundleReport{
+		Path:       "chart",
+		Name:       "repo-chart",
+		Style:      "helm",
+		Resources:  2,
+		Size:       15,
+		Compressed: true,
+		ImageScans: []string{"scan"},
+	}, newBundleReport("chart", bundle, scans))
+}
+
+func TestReportError(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   int
+		reason string
+		path   string
+	}{
+		{readError("a", errors.New("invalid")), ExitValidation, "validation", "a"},
+		{readError("b", fmt.Errorf("reading: %w", &bundlereader.FetchError{Source: "repo", Err: errors.New("timeout")})), ExitFetch, "fetch", "b"},
+		{&Error{Code: ExitAPI, Err: errors.New("forbidden")}, ExitAPI, "api", ""},
+		{errors.New("other"), 1, "unknown", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.code, ExitCode(test.err))
+		report := &Report{}
+		report.SetError(test.err)
+		assert.Equal(t, test.code, report.Error.Code)
+		assert.Equal(t, test.reason, report.Error.Reason)
+		assert.Equal(t, test.path, report.Error.Path)
+	}
+}
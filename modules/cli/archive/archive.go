@@ -0,0 +1,126 @@
+// Package archive exports bundles into a portable archive and imports them
+// into a Fleet Manager, for management clusters that can't reach the git and
+// helm repositories. (fleetbundle)
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// Version of the archive format
+	Version = 1
+
+	manifestFile = "fleet-archive.json"
+	bundlesDir   = "bundles"
+	imagesDir    = "images"
+)
+
+// Manifest describes the content of an archive.
+type Manifest struct {
+	Version  int       `json:"version"`
+	RepoName string    `json:"repoName"`
+	Created  time.Time `json:"created"`
+	Bundles  []Entry   `json:"bundles"`
+	Images   []Image   `json:"images,omitempty"`
+}
+
+// Entry is a bundle in the archive, stored with its image scans as a
+// multi-document YAML file, like the output of 'fleet apply -o'.
+type Entry struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	File       string `json:"file"`
+	ImageScans int    `json:"imageScans,omitempty"`
+}
+
+// Image is a container image referenced by the rendered bundles, stored as a
+// tarball if the archive includes images.
+type Image struct {
+	Ref  string `json:"ref"`
+	File string `json:"file,omitempty"`
+}
+
+// extract reads the archive, keeping the manifest and the bundle files in
+// memory and writing images into dir.
+func extract(filename, dir string) (*Manifest, map[string][]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not a fleet bundle archive: %w", filename, err)
+	}
+	defer gz.Close()
+
+	var (
+		manifest *Manifest
+		files    = map[string][]byte{}
+		tr       = tar.NewReader(gz)
+	)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		name := path.Clean(hdr.Name)
+		if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return nil, nil, fmt.Errorf("invalid file name %s in archive", hdr.Name)
+		}
+
+		switch {
+		case name == manifestFile:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(name, imagesDir+"/"):
+			if dir == "" {
+				continue
+			}
+			if err := extractFile(tr, dir, path.Base(name)); err != nil {
+				return nil, nil, err
+			}
+		default:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, nil, err
+			}
+			files[name] = data
+		}
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%s is not a fleet bundle archive: %s not found", filename, manifestFile)
+	}
+	if manifest.Version != Version {
+		return nil, nil, fmt.Errorf("unsupported archive version %d, expected %d", manifest.Version, Version)
+	}
+
+	return manifest, files, nil
+}
+
+func extractFile(r io.Reader, dir, name string) error {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/fleet/modules/cli/pkg/tarball"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.36
+      containers:
+      - name: web
+        image: nginx:1.25
+`
+
+const fleetYAML = `imageScans:
+- image: nginx
+  tagName: web
+  policy:
+    semver:
+      range: ">1.0"
+`
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "deploy.yaml"), []byte(deployment), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "fleet.yaml"), []byte(fleetYAML), 0644))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	filename := filepath.Join(dir, "bundles.tar.gz")
+	require.NoError(t, Export(context.Background(), "repo", []string{"app"}, filename, nil))
+
+	manifest, files, err := extract(filename, "")
+	require.NoError(t, err)
+	assert.Equal(t, "repo", manifest.RepoName)
+	require.Len(t, manifest.Bundles, 1)
+
+	bundle, scans, err := readEntry(files, manifest.Bundles[0])
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Bundles[0].Name, bundle.Name)
+	assert.Equal(t, "repo", bundle.Labels[fleet.RepoLabel])
+	require.Len(t, scans, 1)
+	assert.Equal(t, "web", scans[0].Spec.TagName)
+	for _, resource := range bundle.Spec.Resources {
+		assert.Equal(t, "base64+gz", resource.Encoding)
+	}
+
+	images, err := Images(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busybox:1.36", "nginx:1.25"}, images)
+}
+
+func TestExtractInvalidNames(t *testing.T) {
+	for _, name := range []string{"..", "../bundle.yaml", "bundles/../../bundle.yaml", "/etc/bundle.yaml"} {
+		filename := filepath.Join(t.TempDir(), "bundles.tar.gz")
+		w, err := tarball.Create(filename)
+		require.NoError(t, err)
+		require.NoError(t, w.Add(name, []byte("{}")))
+		require.NoError(t, w.Close())
+
+		_, _, err = extract(filename, t.TempDir())
+		assert.Error(t, err, name)
+	}
+}
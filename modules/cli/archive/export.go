@@ -0,0 +1,172 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/pkg/tarball"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/name"
+	"github.com/rancher/wrangler/pkg/yaml"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type ExportOptions struct {
+	Apply apply.Options
+	// Images includes the container images referenced by the rendered
+	// bundles in the archive
+	Images bool
+}
+
+// Export reads the bundles from baseDirs like 'fleet apply' and writes them,
+// with their resources compressed, into the archive filename. The bundles are
+// labeled with repoName, so importing a newer archive prunes the bundles
+// removed from the repo.
+func Export(ctx context.Context, repoName string, baseDirs []string, filename string, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	if len(baseDirs) == 0 {
+		baseDirs = []string{"."}
+	}
+
+	applyOpts := opts.Apply
+	applyOpts.Compress = true
+	applyOpts.Labels = map[string]string{}
+	for k, v := range opts.Apply.Labels {
+		applyOpts.Labels[k] = v
+	}
+	applyOpts.Labels[fleet.RepoLabel] = repoName
+
+	paths, err := apply.BundleDirs(baseDirs)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no directories found at the following paths to export: %v", baseDirs)
+	}
+
+	w, err := tarball.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := export(ctx, w, repoName, paths, &applyOpts, opts.Images); err != nil {
+		w.Close()
+		os.Remove(filename)
+		return err
+	}
+	return w.Close()
+}
+
+func export(ctx context.Context, w *tarball.Writer, repoName string, paths []string, applyOpts *apply.Options, images bool) error {
+	manifest := &Manifest{
+		Version:  Version,
+		RepoName: repoName,
+		Created:  time.Now().UTC().Truncate(time.Second),
+	}
+
+	var bundles []*fleet.Bundle
+	for _, p := range paths {
+		bundle, scans, err := apply.Read(ctx, repoName, p, applyOpts)
+		if err != nil {
+			return err
+		}
+		if len(bundle.Spec.Resources) == 0 {
+			logrus.Warnf("%s: %v", p, apply.ErrNoResources)
+			continue
+		}
+
+		objects := []runtime.Object{bundle}
+		for _, scan := range scans {
+			objects = append(objects, scan)
+		}
+		data, err := yaml.Export(objects...)
+		if err != nil {
+			return err
+		}
+
+		entry := Entry{
+			Name:       bundle.Name,
+			Path:       p,
+			File:       path.Join(bundlesDir, bundle.Name+".yaml"),
+			ImageScans: len(scans),
+		}
+		if err := w.Add(entry.File, data); err != nil {
+			return err
+		}
+		manifest.Bundles = append(manifest.Bundles, entry)
+		bundles = append(bundles, bundle)
+	}
+
+	if len(bundles) == 0 {
+		return fmt.Errorf("no resource found at the following paths to export: %v", paths)
+	}
+
+	if images {
+		list, err := exportImages(ctx, w, bundles)
+		if err != nil {
+			return err
+		}
+		manifest.Images = list
+	}
+
+	return w.AddJSON(manifestFile, manifest)
+}
+
+func exportImages(ctx context.Context, w *tarball.Writer, bundles []*fleet.Bundle) ([]Image, error) {
+	refs := map[string]bool{}
+	for _, bundle := range bundles {
+		images, err := Images(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("rendering bundle %s: %w", bundle.Name, err)
+		}
+		for _, image := range images {
+			refs[image] = true
+		}
+	}
+
+	var sorted []string
+	for ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Strings(sorted)
+
+	tmp, err := os.MkdirTemp("", "fleet-images-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var result []Image
+	for _, ref := range sorted {
+		image := Image{
+			Ref:  ref,
+			File: path.Join(imagesDir, name.Hex(ref, 16)+".tar"),
+		}
+		logrus.Infof("exporting image %s", ref)
+		filename := filepath.Join(tmp, path.Base(image.File))
+		if err := pullImage(ctx, ref, filename); err != nil {
+			return nil, fmt.Errorf("pulling image %s: %w", ref, err)
+		}
+		if err := w.AddFile(image.File, filename); err != nil {
+			return nil, err
+		}
+		if err := os.Remove(filename); err != nil {
+			return nil, err
+		}
+		result = append(result, image)
+	}
+
+	return result, nil
+}
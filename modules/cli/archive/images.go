@@ -0,0 +1,123 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/helmdeployer"
+	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/options"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// Images returns the container images of the resources the bundle renders
+// to, with the options of the bundle and of each of its targets.
+func Images(bundle *fleet.Bundle) ([]string, error) {
+	m, err := manifest.New(bundle.Spec.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	optionSets := []fleet.BundleDeploymentOptions{bundle.Spec.BundleDeploymentOptions}
+	for _, target := range bundle.Spec.Targets {
+		optionSets = append(optionSets, options.Merge(bundle.Spec.BundleDeploymentOptions, target.BundleDeploymentOptions))
+	}
+
+	refs := map[string]bool{}
+	for _, opts := range optionSets {
+		objs, err := helmdeployer.Template(bundle.Name, m, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+			var content interface{}
+			if err := json.Unmarshal(data, &content); err != nil {
+				return nil, err
+			}
+			findImages(content, refs)
+		}
+	}
+
+	var result []string
+	for ref := range refs {
+		result = append(result, ref)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// findImages adds the image of every container in obj to refs.
+func findImages(obj interface{}, refs map[string]bool) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for _, field := range containerFields {
+			containers, _ := v[field].([]interface{})
+			for _, c := range containers {
+				container, _ := c.(map[string]interface{})
+				if image, _ := container["image"].(string); image != "" {
+					refs[image] = true
+				}
+			}
+		}
+		for _, value := range v {
+			findImages(value, refs)
+		}
+	case []interface{}:
+		for _, value := range v {
+			findImages(value, refs)
+		}
+	}
+}
+
+func pullImage(ctx context.Context, ref, filename string) error {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return err
+	}
+	img, err := remote.Image(r, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return err
+	}
+	return tarball.WriteToFile(filename, r, img)
+}
+
+// pushImage pushes the image from the tarball filename to registry, keeping
+// the repository path, tag and digest of ref.
+func pushImage(ctx context.Context, ref, filename, registry string) (string, error) {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var tag *name.Tag
+	if t, ok := r.(name.Tag); ok {
+		tag = &t
+	}
+	img, err := tarball.ImageFromPath(filename, tag)
+	if err != nil {
+		return "", err
+	}
+
+	target := registry + "/" + r.Context().RepositoryStr() + ":" + r.Identifier()
+	if _, ok := r.(name.Digest); ok {
+		target = registry + "/" + r.Context().RepositoryStr() + "@" + r.Identifier()
+	}
+	dst, err := name.ParseReference(target)
+	if err != nil {
+		return "", err
+	}
+
+	return dst.String(), remote.Write(dst, img, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
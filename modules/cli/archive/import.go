@@ -0,0 +1,137 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type ImportOptions struct {
+	// Registry the images in the archive are pushed to, they are not
+	// pushed if empty
+	Registry string
+}
+
+// Import creates or updates the bundles and image scans of the archive in
+// the namespace of the client. Bundles of the archive's repo, which are not
+// part of the archive, are deleted, like 'fleet apply' does.
+func Import(ctx context.Context, client *client.Getter, filename string, opts *ImportOptions) error {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+
+	var tmp string
+	if opts.Registry != "" {
+		var err error
+		tmp, err = os.MkdirTemp("", "fleet-images-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+	}
+
+	manifest, files, err := extract(filename, tmp)
+	if err != nil {
+		return err
+	}
+
+	if opts.Registry != "" {
+		if len(manifest.Images) == 0 {
+			logrus.Warnf("%s contains no images, export it with --images", filename)
+		}
+		for _, image := range manifest.Images {
+			if image.File == "" {
+				continue
+			}
+			dst, err := pushImage(ctx, image.Ref, filepath.Join(tmp, path.Base(image.File)), opts.Registry)
+			if err != nil {
+				return fmt.Errorf("pushing image %s: %w", image.Ref, err)
+			}
+			logrus.Infof("pushed image %s to %s", image.Ref, dst)
+		}
+	}
+
+	c, err := client.Get()
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, entry := range manifest.Bundles {
+		bundle, scans, err := readEntry(files, entry)
+		if err != nil {
+			return err
+		}
+		bundle.Namespace = c.Namespace
+		if bundle.Labels[fleet.RepoLabel] != manifest.RepoName {
+			return fmt.Errorf("bundle %s is not labeled with repo %s", bundle.Name, manifest.RepoName)
+		}
+		if err := apply.Save(client, bundle, scans...); err != nil {
+			return err
+		}
+		names[bundle.Name] = true
+	}
+
+	pruned, err := apply.PruneBundlesNotFoundInRepo(client, manifest.RepoName, names)
+	for _, name := range pruned {
+		logrus.Infof("deleted bundle %s, it is not part of the archive", name)
+	}
+	return err
+}
+
+func readEntry(files map[string][]byte, entry Entry) (*fleet.Bundle, []*fleet.ImageScan, error) {
+	data, ok := files[entry.File]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s of bundle %s not found in archive", entry.File, entry.Name)
+	}
+
+	objs, err := yaml.ToObjects(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		bundle *fleet.Bundle
+		scans  []*fleet.ImageScan
+	)
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected object %T in %s", obj, entry.File)
+		}
+		switch u.GetKind() {
+		case "Bundle":
+			bundle = &fleet.Bundle{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, bundle); err != nil {
+				return nil, nil, err
+			}
+		case "ImageScan":
+			scan := &fleet.ImageScan{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, scan); err != nil {
+				return nil, nil, err
+			}
+			scans = append(scans, scan)
+		default:
+			return nil, nil, fmt.Errorf("unexpected kind %s in %s", u.GetKind(), entry.File)
+		}
+	}
+
+	if bundle == nil {
+		return nil, nil, fmt.Errorf("no bundle found in %s", entry.File)
+	}
+	return bundle, scans, nil
+}
@@ -2,6 +2,7 @@ package cmds
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -18,6 +19,14 @@ func NewApply() *cobra.Command {
 	cmd := command.Command(&Apply{}, cobra.Command{
 		Use:   "apply [flags] BUNDLE_NAME PATH...",
 		Short: "Render a bundle into a Kubernetes resource and apply it in the Fleet Manager",
+		Long: `Render a bundle into a Kubernetes resource and apply it in the Fleet Manager.
+
+With --report json, a JSON report of the bundles read from each path, the bundles pruned and the
+error, if any, is printed to stdout. Errors exit with distinct codes:
+
+  2  invalid paths, fleet.yaml files or bundles
+  3  remote content, like a helm chart, couldn't be downloaded
+  4  a request to the Fleet Manager failed`,
 	})
 	command.AddDebug(cmd, &Debug)
 	return cmd
@@ -38,6 +47,7 @@ type Apply struct {
 	PasswordFile      string            `usage:"Path of file containing basic auth password for helm repo"`
 	CACertsFile       string            `usage:"Path of custom cacerts for helm repo" name:"cacerts-file"`
 	SSHPrivateKeyFile string            `usage:"Path of ssh-private-key for helm repo" name:"ssh-privatekey-file"`
+	Report            string            `usage:"Print a report of the applied bundles to stdout, only json is supported"`
 }
 
 func (a *Apply) Run(cmd *cobra.Command, args []string) error {
@@ -113,7 +123,26 @@ func (a *Apply) Run(cmd *cobra.Command, args []string) error {
 		args = args[1:]
 	}
 
-	return apply.Apply(cmd.Context(), Client, name, args, opts)
+	if a.Report == "" {
+		return apply.Apply(cmd.Context(), Client, name, args, opts)
+	}
+
+	if a.Report != "json" {
+		return fmt.Errorf("unknown report format %q, only json is supported", a.Report)
+	}
+	if a.Output == "-" {
+		return fmt.Errorf("--report can't be used with output to stdout")
+	}
+	opts.Report = &apply.Report{RepoName: name}
+	err := apply.Apply(cmd.Context(), Client, name, args, opts)
+	opts.Report.SetError(err)
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(opts.Report); encErr != nil {
+		return encErr
+	}
+	return err
 }
 
 func currentCommit() string {
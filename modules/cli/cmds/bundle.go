@@ -0,0 +1,81 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/archive"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewBundle() *cobra.Command {
+	cmd := command.Command(&Bundle{}, cobra.Command{
+		Short: "Export bundles into an archive and import them, for air-gapped Fleet Managers",
+	})
+	cmd.AddCommand(NewBundleExport(), NewBundleImport())
+	return cmd
+}
+
+type Bundle struct{}
+
+func (b *Bundle) Run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+func NewBundleExport() *cobra.Command {
+	cmd := command.Command(&BundleExport{}, cobra.Command{
+		Use:   "export [flags] REPO_NAME PATH...",
+		Short: "Write the bundles built from the paths into an archive",
+		Long: `Write the bundles built from the paths into an archive.
+
+Bundles are built like 'fleet apply' does, with all resources and helm charts included and compressed.
+They are labeled with the repo name, so importing an archive deletes the bundles of the same repo
+which are not part of it anymore.`,
+		Args: cobra.MinimumNArgs(1),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type BundleExport struct {
+	Output          string            `usage:"Archive file to write" short:"o" default:"fleet-bundles.tar.gz"`
+	Label           map[string]string `usage:"Labels to apply to the bundles" short:"l"`
+	TargetsFile     string            `usage:"Addition source of targets and restrictions to be append"`
+	TargetNamespace string            `usage:"Ensure this bundle goes to this target namespace"`
+	ServiceAccount  string            `usage:"Service account to assign to bundle created" short:"a"`
+	Paused          bool              `usage:"Create bundles in a paused state"`
+	Images          bool              `usage:"Include the container images of the rendered resources"`
+}
+
+func (b *BundleExport) Run(cmd *cobra.Command, args []string) error {
+	return archive.Export(cmd.Context(), args[0], args[1:], b.Output, &archive.ExportOptions{
+		Apply: apply.Options{
+			Labels:          b.Label,
+			TargetsFile:     b.TargetsFile,
+			TargetNamespace: b.TargetNamespace,
+			ServiceAccount:  b.ServiceAccount,
+			Paused:          b.Paused,
+		},
+		Images: b.Images,
+	})
+}
+
+func NewBundleImport() *cobra.Command {
+	cmd := command.Command(&BundleImport{}, cobra.Command{
+		Use:   "import [flags] ARCHIVE",
+		Short: "Create or update the bundles of an archive in the Fleet Manager",
+		Args:  cobra.ExactArgs(1),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type BundleImport struct {
+	Registry string `usage:"Registry to push the images of the archive to, e.g. registry.example.com:5000"`
+}
+
+func (b *BundleImport) Run(cmd *cobra.Command, args []string) error {
+	return archive.Import(cmd.Context(), Client, args[0], &archive.ImportOptions{
+		Registry: b.Registry,
+	})
+}
@@ -0,0 +1,71 @@
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/deploy"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewDeploy() *cobra.Command {
+	cmd := command.Command(&Deploy{}, cobra.Command{
+		Use:   "deploy [flags] PATH",
+		Short: "Deploy a bundle directly to the cluster of the kubeconfig, without a Fleet Manager",
+		Long: `Deploy a bundle directly to the cluster of the kubeconfig, without a Fleet Manager.
+
+The bundle is read like 'fleet apply' does, matched against the targets like 'fleet test' does
+and deployed the way the agent would. Then the command waits for the resources to be ready,
+printing the status of the deployment whenever it changes.`,
+		Args: cobra.MaximumNArgs(1),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type Deploy struct {
+	BundleName       string            `usage:"Name of the deployment and helm release, defaults to the directory name" name:"name"`
+	Target           string            `usage:"Explicit target to match" short:"t"`
+	ClusterName      string            `usage:"Cluster name to match against" short:"N"`
+	Group            string            `usage:"Cluster group to match against" short:"g"`
+	Label            map[string]string `usage:"Cluster labels to match against" short:"l"`
+	GroupLabel       map[string]string `usage:"Cluster group labels to match against" short:"L"`
+	AgentNamespace   string            `usage:"Namespace of the service accounts the deployment can use" default:"cattle-fleet-system"`
+	DefaultNamespace string            `usage:"Namespace for resources without a namespace" default:"default"`
+	Timeout          int               `usage:"Seconds to wait for the bundle to be ready, 0 to not wait" default:"300"`
+}
+
+func (d *Deploy) Run(cmd *cobra.Command, args []string) error {
+	baseDir := "."
+	if len(args) > 0 {
+		baseDir = args[0]
+	}
+
+	name := d.BundleName
+	if name == "" {
+		dir, err := filepath.Abs(baseDir)
+		if err != nil {
+			return err
+		}
+		name = apply.BundleName(filepath.Base(dir), "")
+	}
+
+	return deploy.Deploy(cmd.Context(), baseDir, &deploy.Options{
+		Output:             os.Stdout,
+		Kubeconfig:         Client.Kubeconfig,
+		Context:            Client.Context,
+		Name:               name,
+		AgentNamespace:     d.AgentNamespace,
+		DefaultNamespace:   d.DefaultNamespace,
+		Target:             d.Target,
+		ClusterName:        d.ClusterName,
+		ClusterGroup:       d.Group,
+		ClusterGroupLabels: d.GroupLabel,
+		ClusterLabels:      d.Label,
+		Timeout:            time.Duration(d.Timeout) * time.Second,
+	})
+}
@@ -0,0 +1,56 @@
+package cmds
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/diff"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewDiff() *cobra.Command {
+	cmd := command.Command(&Diff{}, cobra.Command{
+		Use:   "diff [flags] REPO_NAME PATH...",
+		Short: "Show how the bundles built from the paths differ from the bundles in the Fleet Manager",
+		Long: `Show how the bundles built from the paths differ from the bundles in the Fleet Manager.
+
+Bundles are built like 'fleet apply' does and compared with the stored bundles of the same name,
+for every cluster in the namespace. The bundles get the targets of the GitRepo REPO_NAME in the
+namespace, pass --targets-file to use other targets instead.`,
+		Args: cobra.MinimumNArgs(1),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type Diff struct {
+	BundleFile      string            `usage:"Location of the raw Bundle resource yaml" short:"b"`
+	Label           map[string]string `usage:"Labels of the bundles, as passed to fleet apply" short:"l"`
+	TargetsFile     string            `usage:"Targets and restrictions to use instead of the ones of the GitRepo"`
+	TargetNamespace string            `usage:"Ensure this bundle goes to this target namespace, defaults to the one of the GitRepo"`
+	ServiceAccount  string            `usage:"Service account to assign to bundle created, defaults to the one of the GitRepo" short:"a"`
+	ExitCode        bool              `usage:"Exit with an error if any bundle would change"`
+}
+
+func (d *Diff) Run(cmd *cobra.Command, args []string) error {
+	changed, err := diff.Diff(cmd.Context(), Client, args[0], args[1:], &diff.Options{
+		Output: os.Stdout,
+		Apply: apply.Options{
+			BundleFile:      d.BundleFile,
+			Labels:          d.Label,
+			TargetsFile:     d.TargetsFile,
+			TargetNamespace: d.TargetNamespace,
+			ServiceAccount:  d.ServiceAccount,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if changed && d.ExitCode {
+		return errors.New("bundles would change")
+	}
+	return nil
+}
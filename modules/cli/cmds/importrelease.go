@@ -0,0 +1,52 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/importrelease"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewImportRelease() *cobra.Command {
+	cmd := command.Command(&ImportRelease{}, cobra.Command{
+		Use:   "import-release [flags] NAME DIR",
+		Short: "Generate a bundle directory from an existing helm release or labeled objects",
+		Long: `Generate a bundle directory from an existing helm release or labeled objects.
+
+The helm release NAME is read from the cluster of the kubeconfig and written to DIR as a fleet.yaml
+with the release's values. The chart stored in the release is written to DIR, unless --repo is
+given. With --selector, the objects in the namespace matching the selector are written to DIR
+instead, to be deployed as the new release NAME.
+
+The generated fleet.yaml deploys to the same release name and namespace, so the first deployment
+upgrades the release. Use --take-ownership to adopt objects which don't belong to the release. The
+bundle is verified with a dry run through the deployer, which lists the resources it adopts and
+creates.`,
+		Args: cobra.ExactArgs(2),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type ImportRelease struct {
+	ReleaseNamespace string `usage:"Namespace of the release or objects" short:"N" default:"default"`
+	Selector         string `usage:"Import the objects matching the label selector instead of a helm release" short:"l"`
+	Repo             string `usage:"Helm repository of the release's chart, the chart is written to DIR if empty"`
+	TakeOwnership    bool   `usage:"Adopt existing objects which don't belong to the release"`
+	SkipVerify       bool   `usage:"Do not verify the bundle with a dry run"`
+	AgentNamespace   string `usage:"Namespace of the service accounts the dry run can use" default:"cattle-fleet-system"`
+}
+
+func (i *ImportRelease) Run(cmd *cobra.Command, args []string) error {
+	return importrelease.Import(cmd.Context(), args[0], args[1], &importrelease.Options{
+		Output:         cmd.OutOrStdout(),
+		Kubeconfig:     Client.Kubeconfig,
+		Context:        Client.Context,
+		Namespace:      i.ReleaseNamespace,
+		Selector:       i.Selector,
+		Repo:           i.Repo,
+		TakeOwnership:  i.TakeOwnership,
+		SkipVerify:     i.SkipVerify,
+		AgentNamespace: i.AgentNamespace,
+	})
+}
@@ -0,0 +1,41 @@
+package cmds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/lint"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewLint() *cobra.Command {
+	cmd := command.Command(&Lint{}, cobra.Command{
+		Use:   "lint [flags] PATH...",
+		Short: "Validate the fleet.yaml files found in the paths",
+		Long: `Validate the fleet.yaml files found in the paths, like 'fleet apply' would read them.
+
+Unknown fields are errors. Label selectors, values files, target names, rollout limits and
+dependsOn names are checked, too. The JSON schema of fleet.yaml is published in schemas/fleet.yaml.json.`,
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type Lint struct {
+	RepoName string `usage:"Name of the GitRepo, to match dependsOn names exactly" short:"r"`
+}
+
+func (l *Lint) Run(cmd *cobra.Command, args []string) error {
+	problems, err := lint.Lint(l.RepoName, args)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(cmd.OutOrStdout(), p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problems", len(problems))
+	}
+	return nil
+}
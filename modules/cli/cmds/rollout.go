@@ -0,0 +1,102 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/rollout"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewRollout() *cobra.Command {
+	cmd := command.Command(&Rollout{}, cobra.Command{
+		Short: "Pause, resume, promote or redeploy bundles",
+		Long: `Pause, resume, promote or redeploy bundles.
+
+Every action is recorded in the fleet.cattle.io/rollout-audit annotation of the changed resources.`,
+	})
+	cmd.AddCommand(
+		newRolloutAction(&RolloutPause{}, "pause", "Pause a GitRepo, a Bundle or the selected clusters",
+			"Paused bundle deployments keep their current deployment until resumed. If clusters are selected, all bundles are paused on them."),
+		newRolloutAction(&RolloutResume{}, "resume", "Resume a GitRepo, a Bundle or the selected clusters", ""),
+		newRolloutAction(&RolloutPromote{}, "promote", "Deploy the latest version of the selected bundles to the selected clusters",
+			"The bundle deployments are updated regardless of the rollout strategy, and of the bundle or cluster being paused."),
+		newRolloutAction(&RolloutRedeploy{}, "redeploy", "Deploy the selected bundles to the selected clusters again",
+			"The sync generation of the matching bundle deployments is increased, which makes the agents deploy them again."),
+	)
+	return cmd
+}
+
+func newRolloutAction(obj command.Runnable, use, short, long string) *cobra.Command {
+	if long != "" {
+		long = short + ".\n\n" + long
+	}
+	cmd := command.Command(obj, cobra.Command{
+		Use:   use + " [flags]",
+		Short: short,
+		Long:  long,
+		Args:  cobra.NoArgs,
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type Rollout struct{}
+
+func (r *Rollout) Run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type RolloutScope struct {
+	GitRepo      string `usage:"Name of the GitRepo, selects its bundles" name:"gitrepo" short:"g"`
+	Bundle       string `usage:"Name of the Bundle" short:"b"`
+	Cluster      string `usage:"Name of the Cluster" short:"c"`
+	ClusterGroup string `usage:"Name of the ClusterGroup, selects its clusters"`
+	Selector     string `usage:"Label selector for the clusters" short:"l"`
+	Reason       string `usage:"Reason recorded in the audit annotation" short:"m"`
+}
+
+func (s *RolloutScope) options(cmd *cobra.Command) *rollout.Options {
+	return &rollout.Options{
+		Scope: rollout.Scope{
+			GitRepo:         s.GitRepo,
+			Bundle:          s.Bundle,
+			Cluster:         s.Cluster,
+			ClusterGroup:    s.ClusterGroup,
+			ClusterSelector: s.Selector,
+		},
+		Output: cmd.OutOrStdout(),
+		Reason: s.Reason,
+	}
+}
+
+type RolloutPause struct {
+	RolloutScope
+}
+
+func (r *RolloutPause) Run(cmd *cobra.Command, args []string) error {
+	return rollout.Pause(Client, r.options(cmd))
+}
+
+type RolloutResume struct {
+	RolloutScope
+}
+
+func (r *RolloutResume) Run(cmd *cobra.Command, args []string) error {
+	return rollout.Resume(Client, r.options(cmd))
+}
+
+type RolloutPromote struct {
+	RolloutScope
+}
+
+func (r *RolloutPromote) Run(cmd *cobra.Command, args []string) error {
+	return rollout.Promote(Client, r.options(cmd))
+}
+
+type RolloutRedeploy struct {
+	RolloutScope
+}
+
+func (r *RolloutRedeploy) Run(cmd *cobra.Command, args []string) error {
+	return rollout.Redeploy(Client, r.options(cmd))
+}
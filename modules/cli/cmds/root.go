@@ -27,6 +27,14 @@ func App() *cobra.Command {
 	root.AddCommand(
 		NewApply(),
 		NewTest(),
+		NewDiff(),
+		NewLint(),
+		NewStatus(),
+		NewRollout(),
+		NewBundle(),
+		NewDeploy(),
+		NewSupportBundle(),
+		NewImportRelease(),
 	)
 
 	return root
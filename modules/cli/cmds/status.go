@@ -0,0 +1,42 @@
+package cmds
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/status"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewStatus() *cobra.Command {
+	cmd := command.Command(&Status{}, cobra.Command{
+		Use:     "status [flags] gitrepo|bundle|cluster NAME",
+		Aliases: []string{"get"},
+		Short:   "Show the status of a GitRepo, Bundle or Cluster and the resources related to it",
+		Long: `Show the status of a GitRepo, Bundle or Cluster and the resources related to it.
+
+GitRepos are shown with their bundles, bundles with their bundle deployments and bundle deployments
+with the cluster they target. Clusters are shown with their bundle deployments. Non-ready and modified
+resources, dependencies a bundle deployment waits for and the progress of the rollout partitions are
+listed below each resource.`,
+		Args: cobra.ExactArgs(2),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type Status struct {
+	Output   string `usage:"Output format, table or json" short:"o" default:"table"`
+	Watch    bool   `usage:"Print the status again whenever it changes" short:"w"`
+	Interval int    `usage:"Seconds between status checks in watch mode" default:"2"`
+}
+
+func (s *Status) Run(cmd *cobra.Command, args []string) error {
+	return status.Status(cmd.Context(), Client, args[0], args[1], &status.Options{
+		Output:   cmd.OutOrStdout(),
+		Format:   s.Output,
+		Watch:    s.Watch,
+		Interval: time.Duration(s.Interval) * time.Second,
+	})
+}
@@ -0,0 +1,43 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rancher/fleet/modules/cli/supportbundle"
+	command "github.com/rancher/wrangler-cli"
+)
+
+func NewSupportBundle() *cobra.Command {
+	cmd := command.Command(&SupportBundle{}, cobra.Command{
+		Use:   "support-bundle [flags] gitrepo|cluster NAME",
+		Short: "Collect the resources and logs related to a GitRepo or Cluster into an archive for troubleshooting",
+		Long: `Collect the resources and logs related to a GitRepo or Cluster into an archive for troubleshooting.
+
+For a GitRepo, the archive contains its bundles, bundle deployments, contents, the clusters they are
+deployed to and the jobs and pods of gitjob. For a Cluster, it contains its bundle deployments, their
+bundles and contents. Helm release secrets are collected if the kubeconfig points to the cluster they
+are deployed to. The pods and logs of the system namespace and of the local agent are always included.
+
+Secrets are stripped of their data. A summary of the suspicious states found is printed and stored in
+the archive as summary.txt.`,
+		Args: cobra.ExactArgs(2),
+	})
+	command.AddDebug(cmd, &Debug)
+	return cmd
+}
+
+type SupportBundle struct {
+	Output string `usage:"Archive to write" short:"o" default:"fleet-support-bundle.tar.gz"`
+	Decode bool   `usage:"Decompress the resources of bundles and contents"`
+	Tail   int    `usage:"Number of log lines per container, 0 for all" default:"1000"`
+}
+
+func (s *SupportBundle) Run(cmd *cobra.Command, args []string) error {
+	_, err := supportbundle.Collect(cmd.Context(), Client, args[0], args[1], s.Output, &supportbundle.Options{
+		Output:          cmd.OutOrStdout(),
+		SystemNamespace: SystemNamespace,
+		Decode:          s.Decode,
+		TailLines:       int64(s.Tail),
+	})
+	return err
+}
@@ -20,12 +20,14 @@ func NewTest() *cobra.Command {
 
 type Test struct {
 	BundleInputArgs
-	Quiet      bool              `usage:"Just print the match and don't print the resources" short:"q"`
-	Group      string            `usage:"Cluster group to match against" short:"g"`
-	Name       string            `usage:"Cluster name to match against" short:"N"`
-	Label      map[string]string `usage:"Cluster labels to match against" short:"l"`
-	GroupLabel map[string]string `usage:"Cluster group labels to match against" short:"L"`
-	Target     string            `usage:"Explicit target to match" short:"t"`
+	Quiet        bool              `usage:"Just print the match and don't print the resources" short:"q"`
+	Group        string            `usage:"Cluster group to match against" short:"g"`
+	Name         string            `usage:"Cluster name to match against" short:"N"`
+	Label        map[string]string `usage:"Cluster labels to match against" short:"l"`
+	GroupLabel   map[string]string `usage:"Cluster group labels to match against" short:"L"`
+	Target       string            `usage:"Explicit target to match" short:"t"`
+	Clusters     bool              `usage:"Match against the clusters and cluster groups in the namespace of the Fleet Manager"`
+	ClustersFile string            `usage:"Match against the Cluster and ClusterGroup objects in this YAML file, e.g. from 'kubectl get clusters,clustergroups -o yaml'"`
 }
 
 func (m *Test) Run(cmd *cobra.Command, args []string) error {
@@ -50,6 +52,19 @@ func (m *Test) Run(cmd *cobra.Command, args []string) error {
 		opts.Output = nil
 	}
 
+	if m.Clusters || m.ClustersFile != "" {
+		var err error
+		if m.ClustersFile != "" {
+			opts.Clusters, opts.ClusterGroups, err = match.ClustersFromFile(m.ClustersFile)
+		} else {
+			opts.Clusters, opts.ClusterGroups, err = match.ClustersFromManager(Client)
+		}
+		if err != nil {
+			return err
+		}
+		return match.Match(cmd.Context(), opts)
+	}
+
 	if opts.ClusterGroup == "" &&
 		len(opts.ClusterLabels) == 0 &&
 		len(opts.ClusterGroupLabels) == 0 &&
@@ -0,0 +1,287 @@
+// Package deploy deploys a bundle directly to a cluster, with the code the
+// agent uses, but without a Fleet Manager. (fleetdeploy)
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/modules/agent/pkg/deployer"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlematcher"
+	"github.com/rancher/fleet/pkg/bundlereader"
+	"github.com/rancher/fleet/pkg/helmdeployer"
+	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/target"
+
+	"github.com/rancher/wrangler/pkg/apply"
+	"github.com/rancher/wrangler/pkg/generated/controllers/core"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultAgentNamespace = "cattle-fleet-system"
+	defaultNamespace      = "default"
+)
+
+var ErrNotReady = errors.New("bundle deployment is not ready")
+
+type Options struct {
+	Output     io.Writer
+	Kubeconfig string
+	Context    string
+
+	// Name of the bundle deployment and its helm release
+	Name string
+	// AgentNamespace contains the service accounts the deployment can
+	// impersonate
+	AgentNamespace string
+	// DefaultNamespace is used for resources without a namespace
+	DefaultNamespace string
+
+	// Target, or cluster name, group and labels to match the bundle's
+	// targets against
+	Target             string
+	ClusterName        string
+	ClusterGroup       string
+	ClusterGroupLabels map[string]string
+	ClusterLabels      map[string]string
+
+	// Timeout for the bundle to become ready, no waiting if zero
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// Deploy reads the bundle in baseDir, merges the options of the matching
+// target and deploys it like the agent does. Unless the timeout is zero, it
+// waits for the deployed resources to be ready and not modified, printing
+// the status in the format of BundleDeploymentStatus whenever it changes.
+func Deploy(ctx context.Context, baseDir string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	setDefaults(opts)
+
+	bundle, _, err := bundlereader.Open(ctx, opts.Name, baseDir, "", nil)
+	if err != nil {
+		return err
+	}
+
+	bd, m, err := bundleDeployment(bundle, opts)
+	if err != nil {
+		return err
+	}
+
+	manager, err := newManager(ctx, m, opts)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("deploying bundle %s, deployment ID %s", bd.Name, bd.Spec.DeploymentID)
+	release, err := manager.Deploy(bd)
+	if err != nil {
+		return err
+	}
+	bd.Status.Release = release
+	bd.Status.AppliedDeploymentID = bd.Spec.DeploymentID
+	fmt.Fprintf(opts.Output, "# deployed release %s\n", release)
+
+	if opts.Timeout <= 0 {
+		return nil
+	}
+	return wait(ctx, manager, bd, opts)
+}
+
+func setDefaults(opts *Options) {
+	if opts.AgentNamespace == "" {
+		opts.AgentNamespace = defaultAgentNamespace
+	}
+	if opts.DefaultNamespace == "" {
+		opts.DefaultNamespace = defaultNamespace
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+}
+
+// bundleDeployment returns the bundle deployment the Fleet Manager would
+// create for the bundle's matching target.
+func bundleDeployment(bundle *fleet.Bundle, opts *Options) (*fleet.BundleDeployment, *manifest.Manifest, error) {
+	bm, err := bundlematcher.New(bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var match *fleet.BundleTarget
+	if opts.Target != "" {
+		match = bm.MatchForTarget(opts.Target)
+	} else {
+		group := opts.ClusterGroup
+		if group == "" && opts.ClusterName == "" && len(opts.ClusterLabels) == 0 && len(opts.ClusterGroupLabels) == 0 {
+			group = "default"
+		}
+		match = bm.Match(opts.ClusterName, map[string]map[string]string{group: opts.ClusterGroupLabels}, opts.ClusterLabels)
+	}
+	if match == nil {
+		return nil, nil, errors.New("no target of the bundle matches the cluster")
+	}
+	logrus.Infof("matched target %s", match.Name)
+
+	m, err := manifest.New(bundle.Spec.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options, deploymentID, err := target.DeploymentOptions(bundle, m, match, opts.ClusterLabels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &fleet.BundleDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   bundle.Name,
+			Labels: bundle.Labels,
+		},
+		Spec: fleet.BundleDeploymentSpec{
+			DeploymentID: deploymentID,
+			Options:      options,
+		},
+	}, m, nil
+}
+
+// DryRun reads the bundle in baseDir like Deploy and installs or upgrades its
+// release without changing the cluster. It fails if the release can't adopt
+// existing resources and returns the resources the release would contain.
+func DryRun(ctx context.Context, baseDir string, opts *Options) (*helmdeployer.Resources, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	setDefaults(opts)
+
+	bundle, _, err := bundlereader.Open(ctx, opts.Name, baseDir, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bd, m, err := bundleDeployment(bundle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	helm, _, err := newHelm(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.DryRun(bd.Name, m, bd.Spec.Options)
+}
+
+func newManager(ctx context.Context, m *manifest.Manifest, opts *Options) (*deployer.Manager, error) {
+	helm, restConfig, err := newHelm(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	apply, err := apply.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployer.NewManager(opts.AgentNamespace, opts.DefaultNamespace, opts.DefaultNamespace, "",
+		nil, &lookup{manifest: m}, helm, apply), nil
+}
+
+func newHelm(ctx context.Context, opts *Options) (*helmdeployer.Helm, *rest.Config, error) {
+	getter := genericclioptions.NewConfigFlags(true)
+	getter.KubeConfig = &opts.Kubeconfig
+	getter.Context = &opts.Context
+
+	restConfig, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coreFactory, err := core.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	corev := coreFactory.Core().V1()
+
+	helm, err := helmdeployer.NewHelm(opts.AgentNamespace, opts.DefaultNamespace, opts.DefaultNamespace, "", getter,
+		corev.ServiceAccount().Cache(), corev.ConfigMap().Cache(), corev.Secret().Cache())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := coreFactory.Start(ctx, 1); err != nil {
+		return nil, nil, err
+	}
+	if err := coreFactory.Sync(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return helm, restConfig, nil
+}
+
+func wait(ctx context.Context, manager *deployer.Manager, bd *fleet.BundleDeployment, opts *Options) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var last *fleet.BundleDeploymentStatus
+	for {
+		status, err := manager.MonitorBundle(bd)
+		if err != nil {
+			return err
+		}
+		bd.Status.Ready = status.Ready
+		bd.Status.NonModified = status.NonModified
+		bd.Status.NonReadyStatus = status.NonReadyStatus
+		bd.Status.ModifiedStatus = status.ModifiedStatus
+
+		if last == nil || !reflect.DeepEqual(*last, bd.Status) {
+			data, err := yaml.Marshal(bd.Status)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Output, "---\n%s", data)
+			last = bd.Status.DeepCopy()
+		}
+
+		if status.Ready && status.NonModified {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s", ErrNotReady, opts.Timeout)
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// lookup returns the manifest of the bundle read from disk, instead of the
+// content resource of the Fleet Manager.
+type lookup struct {
+	manifest *manifest.Manifest
+}
+
+func (l *lookup) Get(id string) (*manifest.Manifest, error) {
+	_, digest, err := l.manifest.Content()
+	if err != nil {
+		return nil, err
+	}
+	if id != digest {
+		return nil, fmt.Errorf("manifest %s not found", id)
+	}
+	return l.manifest, nil
+}
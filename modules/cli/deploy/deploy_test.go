@@ -0,0 +1,49 @@
+package deploy
+
+import (
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBundleDeployment(t *testing.T) {
+	bundle := &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: fleet.BundleSpec{
+			BundleDeploymentOptions: fleet.BundleDeploymentOptions{DefaultNamespace: "app"},
+			Resources:               []fleet.BundleResource{{Name: "cm.yaml", Content: "kind: ConfigMap"}},
+			Targets: []fleet.BundleTarget{
+				{
+					Name:                    "prod",
+					ClusterSelector:         &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					BundleDeploymentOptions: fleet.BundleDeploymentOptions{TargetNamespace: "app-prod"},
+				},
+				{Name: "default", ClusterGroup: "default"},
+			},
+		},
+	}
+
+	bd, m, err := bundleDeployment(bundle, &Options{ClusterLabels: map[string]string{"env": "prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, "app", bd.Name)
+	assert.Equal(t, "app-prod", bd.Spec.Options.TargetNamespace)
+
+	l := &lookup{manifest: m}
+	_, digest, err := m.Content()
+	require.NoError(t, err)
+	assert.Contains(t, bd.Spec.DeploymentID, digest+":")
+	_, err = l.Get(digest)
+	assert.NoError(t, err)
+	_, err = l.Get("s-other")
+	assert.Error(t, err)
+
+	bd, _, err = bundleDeployment(bundle, &Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "", bd.Spec.Options.TargetNamespace)
+
+	_, _, err = bundleDeployment(bundle, &Options{ClusterGroup: "other"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,369 @@
+// Package diff compares the bundles built from a local checkout with the
+// bundles stored in the Fleet Manager. (fleetapply)
+//
+// It is available in the fleet CLI as "diff" sub command, e.g. to review
+// changes to a git repository in CI, before they are merged.
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlematcher"
+	"github.com/rancher/fleet/pkg/helmdeployer"
+	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/target"
+
+	"github.com/rancher/wrangler/pkg/yaml"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type Options struct {
+	Output io.Writer
+	// Apply holds the options to read the local bundles, as used by
+	// 'fleet apply'
+	Apply apply.Options
+}
+
+// Diff builds the bundles for repoName from the baseDirs and prints the
+// differences to the stored bundles for every cluster in the namespace of
+// the client. The targets of the bundles are those of the GitRepo, unless a
+// targets file is given. It returns true if any bundle would change.
+func Diff(ctx context.Context, client *client.Getter, repoName string, baseDirs []string, opts *Options) (bool, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	c, err := client.Get()
+	if err != nil {
+		return false, err
+	}
+
+	applyOpts := opts.Apply
+	if applyOpts.TargetsFile == "" {
+		gitrepo, err := c.Fleet.GitRepo().Get(c.Namespace, repoName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// a new GitRepo, which targets the default cluster group
+			gitrepo = &fleet.GitRepo{}
+		} else if err != nil {
+			return false, err
+		}
+		applyOpts.Targets = target.GitRepoTargets(gitrepo)
+		if applyOpts.ServiceAccount == "" {
+			applyOpts.ServiceAccount = gitrepo.Spec.ServiceAccount
+		}
+		if applyOpts.TargetNamespace == "" {
+			applyOpts.TargetNamespace = gitrepo.Spec.TargetNamespace
+		}
+	}
+
+	bundles, err := apply.Bundles(ctx, repoName, baseDirs, &applyOpts)
+	if err != nil {
+		return false, err
+	}
+
+	clusters, err := c.Fleet.Cluster().List(c.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	clusterGroups, err := c.Fleet.ClusterGroup().List(c.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	stored, err := c.Fleet.Bundle().List(c.Namespace, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{fleet.RepoLabel: repoName}).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	e := &env{
+		out: out,
+	}
+	for i := range clusters.Items {
+		e.clusters = append(e.clusters, &clusters.Items[i])
+	}
+	for i := range clusterGroups.Items {
+		e.clusterGroups = append(e.clusterGroups, &clusterGroups.Items[i])
+	}
+
+	pruned := map[string]*fleet.Bundle{}
+	for i := range stored.Items {
+		pruned[stored.Items[i].Name] = &stored.Items[i]
+	}
+
+	changed := false
+	diff := func(oldBundle, newBundle *fleet.Bundle) error {
+		bundle := newBundle
+		if bundle == nil {
+			bundle = oldBundle
+		}
+		bds, err := c.Fleet.BundleDeployment().List("", metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{
+				"fleet.cattle.io/bundle-name":      bundle.Name,
+				"fleet.cattle.io/bundle-namespace": bundle.Namespace,
+			}).String(),
+		})
+		if err != nil {
+			return err
+		}
+		e.deployments = map[string]*fleet.BundleDeployment{}
+		for i := range bds.Items {
+			e.deployments[bds.Items[i].Namespace] = &bds.Items[i]
+		}
+
+		bundleChanged, err := e.diffBundle(oldBundle, newBundle)
+		if err != nil {
+			return fmt.Errorf("bundle %s: %w", bundle.Name, err)
+		}
+		changed = changed || bundleChanged
+		return nil
+	}
+
+	for _, bundle := range bundles {
+		bundle.Namespace = c.Namespace
+		delete(pruned, bundle.Name)
+
+		old, err := c.Fleet.Bundle().Get(bundle.Namespace, bundle.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			old = nil
+		} else if err != nil {
+			return false, err
+		}
+		if err := diff(old, bundle); err != nil {
+			return false, err
+		}
+	}
+
+	names := make([]string, 0, len(pruned))
+	for name := range pruned {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := diff(pruned[name], nil); err != nil {
+			return false, err
+		}
+	}
+
+	return changed, nil
+}
+
+type env struct {
+	out           io.Writer
+	clusters      []*fleet.Cluster
+	clusterGroups []*fleet.ClusterGroup
+	// deployments of the current bundle by cluster namespace
+	deployments map[string]*fleet.BundleDeployment
+}
+
+// side is the stored or the local version of a bundle.
+type side struct {
+	bundle   *fleet.Bundle
+	manifest *manifest.Manifest
+	matcher  *bundlematcher.BundleMatch
+	rendered map[string]map[string]string
+}
+
+func newSide(bundle *fleet.Bundle) (*side, error) {
+	if bundle == nil {
+		return &side{}, nil
+	}
+	m, err := manifest.New(bundle.Spec.Resources)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := bundlematcher.New(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &side{
+		bundle:   bundle,
+		manifest: m,
+		matcher:  bm,
+		rendered: map[string]map[string]string{},
+	}, nil
+}
+
+// match returns the matched target name, merged options and deployment ID
+// of the bundle for the cluster. The name is empty if no target matched.
+func (s *side) match(cluster *fleet.Cluster, groups map[string]map[string]string) (string, fleet.BundleDeploymentOptions, string, error) {
+	if s.bundle == nil {
+		return "", fleet.BundleDeploymentOptions{}, "", nil
+	}
+	t := s.matcher.Match(cluster.Name, groups, cluster.Labels)
+	if t == nil {
+		return "", fleet.BundleDeploymentOptions{}, "", nil
+	}
+	opts, deploymentID, err := target.DeploymentOptions(s.bundle, s.manifest, t, cluster.Labels)
+	return t.Name, opts, deploymentID, err
+}
+
+// render returns the rendered resources by key, it caches the result per
+// deployment ID.
+func (s *side) render(opts fleet.BundleDeploymentOptions, deploymentID string) (map[string]string, error) {
+	if s.bundle == nil || deploymentID == "" {
+		return nil, nil
+	}
+	if r, ok := s.rendered[deploymentID]; ok {
+		return r, nil
+	}
+
+	objs, err := helmdeployer.Template(s.bundle.Name, s.manifest, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for _, obj := range objs {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		key := strings.Join([]string{gvk.Group, gvk.Version, gvk.Kind, m.GetNamespace(), m.GetName()}, "/")
+		data, err := yaml.Export(obj)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = string(data)
+	}
+	s.rendered[deploymentID] = result
+	return result, nil
+}
+
+// diffBundle prints the changes of the bundle for every cluster, either
+// bundle is nil if it's created or deleted. It returns true if the bundle
+// changed.
+func (e *env) diffBundle(oldBundle, newBundle *fleet.Bundle) (bool, error) {
+	oldSide, err := newSide(oldBundle)
+	if err != nil {
+		return false, err
+	}
+	newSide, err := newSide(newBundle)
+	if err != nil {
+		return false, err
+	}
+
+	// creating and deleting a bundle is a change, even if it doesn't
+	// target any cluster
+	changed := true
+	switch {
+	case oldBundle == nil:
+		fmt.Fprintf(e.out, "Bundle %s/%s would be created\n", newBundle.Namespace, newBundle.Name)
+	case newBundle == nil:
+		fmt.Fprintf(e.out, "Bundle %s/%s would be deleted, it is not found in the paths anymore\n", oldBundle.Namespace, oldBundle.Name)
+	default:
+		fmt.Fprintf(e.out, "Bundle %s/%s\n", newBundle.Namespace, newBundle.Name)
+		changed = false
+	}
+
+	for _, cluster := range e.clusters {
+		groups := target.ClusterGroupLabels(target.ClusterGroupsForCluster(cluster, e.clusterGroups))
+
+		oldTarget, oldOpts, oldID, err := oldSide.match(cluster, groups)
+		if err != nil {
+			return false, err
+		}
+		newTarget, newOpts, newID, err := newSide.match(cluster, groups)
+		if err != nil {
+			return false, err
+		}
+		if oldTarget == "" && newTarget == "" {
+			continue
+		}
+
+		// the deployment ID currently rolled out to the cluster, if any
+		currentID := oldID
+		if bd, ok := e.deployments[cluster.Status.Namespace]; ok && bd.Spec.DeploymentID != "" {
+			currentID = bd.Spec.DeploymentID
+		}
+
+		if oldTarget == newTarget && currentID == newID {
+			fmt.Fprintf(e.out, "  cluster %s/%s: target %s, unchanged\n", cluster.Namespace, cluster.Name, newTarget)
+			continue
+		}
+
+		changed = true
+		fmt.Fprintf(e.out, "  cluster %s/%s: target %s -> %s, deploymentID %s -> %s\n",
+			cluster.Namespace, cluster.Name, orNone(oldTarget), orNone(newTarget), orNone(currentID), orNone(newID))
+
+		oldResources, err := oldSide.render(oldOpts, oldID)
+		if err != nil {
+			return false, err
+		}
+		newResources, err := newSide.render(newOpts, newID)
+		if err != nil {
+			return false, err
+		}
+		if err := writeDiff(e.out, oldResources, newResources); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// writeDiff writes a unified diff for every resource that differs.
+func writeDiff(out io.Writer, oldResources, newResources map[string]string) error {
+	keys := map[string]bool{}
+	for k := range oldResources {
+		keys[k] = true
+	}
+	for k := range newResources {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		if oldResources[key] == newResources[key] {
+			continue
+		}
+		fromFile, toFile := "a/"+key, "b/"+key
+		if _, ok := oldResources[key]; !ok {
+			fromFile = "/dev/null"
+		}
+		if _, ok := newResources[key]; !ok {
+			toFile = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(oldResources[key]),
+			B:        difflib.SplitLines(newResources[key]),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newBundle(value string) *fleet.Bundle {
+	return &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "repo-app"},
+		Spec: fleet.BundleSpec{
+			BundleDeploymentOptions: fleet.BundleDeploymentOptions{DefaultNamespace: "app"},
+			Resources: []fleet.BundleResource{{
+				Name:    "cm.yaml",
+				Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  key: " + value + "\n",
+			}},
+			Targets: []fleet.BundleTarget{{Name: "prod", ClusterName: "prod"}},
+		},
+	}
+}
+
+func newEnv(out *bytes.Buffer) *env {
+	return &env{
+		out: out,
+		clusters: []*fleet.Cluster{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "prod"},
+				Status:     fleet.ClusterStatus{Namespace: "cluster-fleet-default-prod"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "dev"},
+				Status:     fleet.ClusterStatus{Namespace: "cluster-fleet-default-dev"},
+			},
+		},
+		deployments: map[string]*fleet.BundleDeployment{},
+	}
+}
+
+func TestDiffBundleUnchanged(t *testing.T) {
+	out := &bytes.Buffer{}
+	changed, err := newEnv(out).diffBundle(newBundle("a"), newBundle("a"))
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "Bundle fleet-default/repo-app\n  cluster fleet-default/prod: target prod, unchanged\n", out.String())
+}
+
+func TestDiffBundleChanged(t *testing.T) {
+	out := &bytes.Buffer{}
+	changed, err := newEnv(out).diffBundle(newBundle("a"), newBundle("b"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, out.String(), "  cluster fleet-default/prod: target prod -> prod, deploymentID ")
+	assert.Contains(t, out.String(), "--- a//v1/ConfigMap//app\n+++ b//v1/ConfigMap//app\n")
+	assert.Contains(t, out.String(), "-  key: a\n+  key: b\n")
+	assert.NotContains(t, out.String(), "fleet-default/dev")
+}
+
+func TestDiffBundlePruned(t *testing.T) {
+	out := &bytes.Buffer{}
+	changed, err := newEnv(out).diffBundle(newBundle("a"), nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, out.String(), "Bundle fleet-default/repo-app would be deleted, it is not found in the paths anymore\n")
+	assert.Contains(t, out.String(), "  cluster fleet-default/prod: target prod -> <none>, deploymentID ")
+	assert.Contains(t, out.String(), "+++ /dev/null\n")
+}
+
+func TestDiffBundleRolledOut(t *testing.T) {
+	// the cluster still runs an older deployment of the unchanged bundle
+	out := &bytes.Buffer{}
+	e := newEnv(out)
+	e.deployments["cluster-fleet-default-prod"] = &fleet.BundleDeployment{
+		Spec: fleet.BundleDeploymentSpec{DeploymentID: "s-old:123"},
+	}
+	changed, err := e.diffBundle(newBundle("a"), newBundle("a"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, out.String(), "deploymentID s-old:123 -> s-")
+}
@@ -0,0 +1,167 @@
+// Package importrelease generates a bundle directory from a helm release or
+// from labeled objects of a namespace, so existing deployments can be moved
+// to fleet. (fleetimport)
+package importrelease
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/fleetyaml"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+const fleetYAML = "fleet.yaml"
+
+type Options struct {
+	Output     io.Writer
+	Kubeconfig string
+	Context    string
+	// Namespace of the release or objects
+	Namespace string
+	// Selector selects the objects to import, instead of a helm release
+	Selector string
+	// Repo of the release's chart. If empty, the chart stored in the
+	// release is written to the bundle directory.
+	Repo string
+	// TakeOwnership lets the first deployment adopt existing resources,
+	// which don't belong to the release
+	TakeOwnership bool
+	// SkipVerify skips the dry run, which verifies the bundle adopts the
+	// existing resources
+	SkipVerify bool
+	// AgentNamespace used by the dry run, see deploy.Options
+	AgentNamespace string
+}
+
+// Import writes a bundle directory dir for the helm release name, or for the
+// objects matching the selector, in the namespace of the options. The
+// generated fleet.yaml deploys to a release with the same name, so the
+// bundle upgrades the existing release, or installs a new release adopting
+// the objects. Unless skipped, a dry run through the deployer verifies the
+// bundle can be deployed without conflicts.
+func Import(ctx context.Context, name, dir string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.Output == nil {
+		opts.Output = io.Discard
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty", dir)
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	getter := genericclioptions.NewConfigFlags(true)
+	getter.KubeConfig = &opts.Kubeconfig
+	getter.Context = &opts.Context
+	getter.Namespace = &opts.Namespace
+
+	var (
+		fy  *fleetyaml.FleetYAML
+		err error
+	)
+	if opts.Selector == "" {
+		fy, err = fromRelease(getter, name, dir, opts)
+	} else {
+		fy, err = fromObjects(ctx, getter, name, dir, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(fy)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, fleetYAML), data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Output, "# wrote bundle %s to %s\n", name, dir)
+
+	if opts.SkipVerify {
+		return nil
+	}
+	return verify(ctx, getter, name, dir, opts)
+}
+
+// fromRelease writes the chart of the release, unless a repo is given, and
+// returns the fleet.yaml deploying it with the release's values.
+func fromRelease(getter genericclioptions.RESTClientGetter, name, dir string, opts *Options) (*fleetyaml.FleetYAML, error) {
+	cfg := &action.Configuration{}
+	if err := cfg.Init(getter, opts.Namespace, "secrets", logrus.Debugf); err != nil {
+		return nil, err
+	}
+	rel, err := action.NewGet(cfg).Run(name)
+	if err != nil {
+		return nil, err
+	}
+	return writeRelease(rel, dir, opts)
+}
+
+func writeRelease(rel *release.Release, dir string, opts *Options) (*fleetyaml.FleetYAML, error) {
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return nil, fmt.Errorf("release %s/%s has no chart", rel.Namespace, rel.Name)
+	}
+
+	helm := &fleet.HelmOptions{
+		ReleaseName:   rel.Name,
+		TakeOwnership: opts.TakeOwnership,
+	}
+	if len(rel.Config) > 0 {
+		helm.Values = &fleet.GenericMap{Data: rel.Config}
+	}
+
+	if opts.Repo != "" {
+		helm.Repo = opts.Repo
+		helm.Chart = rel.Chart.Metadata.Name
+		helm.Version = rel.Chart.Metadata.Version
+	} else {
+		if err := chartutil.SaveDir(rel.Chart, dir); err != nil {
+			return nil, err
+		}
+		// the raw values.yaml is not stored in the release
+		values := filepath.Join(dir, rel.Chart.Name(), chartutil.ValuesfileName)
+		if _, err := os.Stat(values); os.IsNotExist(err) && len(rel.Chart.Values) > 0 {
+			data, err := yaml.Marshal(rel.Chart.Values)
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(values, data, 0644); err != nil {
+				return nil, err
+			}
+		}
+		helm.Chart = "./" + rel.Chart.Name()
+	}
+
+	logrus.Infof("importing release %s/%s, chart %s-%s, revision %d", rel.Namespace, rel.Name,
+		rel.Chart.Metadata.Name, rel.Chart.Metadata.Version, rel.Version)
+	return newFleetYAML(rel.Namespace, helm), nil
+}
+
+func newFleetYAML(namespace string, helm *fleet.HelmOptions) *fleetyaml.FleetYAML {
+	return &fleetyaml.FleetYAML{
+		BundleSpec: fleet.BundleSpec{
+			BundleDeploymentOptions: fleet.BundleDeploymentOptions{
+				DefaultNamespace: namespace,
+				Helm:             helm,
+			},
+		},
+	}
+}
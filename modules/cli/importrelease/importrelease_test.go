@@ -0,0 +1,96 @@
+package importrelease
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/rancher/fleet/pkg/bundlereader"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestWriteRelease(t *testing.T) {
+	dir := t.TempDir()
+	rel := &release.Release{
+		Name:      "app",
+		Namespace: "apps",
+		Version:   3,
+		Config:    map[string]interface{}{"replicas": float64(2)},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "app-chart", Version: "1.2.3"},
+			Values:   map[string]interface{}{"replicas": float64(1)},
+			Templates: []*chart.File{
+				{Name: "templates/cm.yaml", Data: []byte("kind: ConfigMap\napiVersion: v1\nmetadata:\n  name: app\n")},
+			},
+		},
+	}
+
+	fy, err := writeRelease(rel, dir, &Options{TakeOwnership: true})
+	require.NoError(t, err)
+	data, err := yaml.Marshal(fy)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fleetYAML), data, 0644))
+
+	assert.FileExists(t, filepath.Join(dir, "app-chart", "Chart.yaml"))
+	assert.FileExists(t, filepath.Join(dir, "app-chart", "values.yaml"))
+	assert.FileExists(t, filepath.Join(dir, "app-chart", "templates", "cm.yaml"))
+
+	bundle, _, err := bundlereader.Open(context.Background(), "app", dir, "", nil)
+	require.NoError(t, err)
+	helm := bundle.Spec.Helm
+	require.NotNil(t, helm)
+	assert.Equal(t, "apps", bundle.Spec.DefaultNamespace)
+	assert.Equal(t, "./app-chart", helm.Chart)
+	assert.Equal(t, "app", helm.ReleaseName)
+	assert.True(t, helm.TakeOwnership)
+	assert.Equal(t, map[string]interface{}{"replicas": float64(2)}, helm.Values.Data)
+
+	fy, err = writeRelease(rel, t.TempDir(), &Options{Repo: "https://charts.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://charts.example.com", fy.Helm.Repo)
+	assert.Equal(t, "app-chart", fy.Helm.Chart)
+	assert.Equal(t, "1.2.3", fy.Helm.Version)
+}
+
+func TestClean(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name":            "app",
+			"namespace":       "apps",
+			"uid":             "1234",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{"app": "app"},
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		"spec": map[string]interface{}{
+			"clusterIP": "10.43.0.10",
+			"ports":     []interface{}{map[string]interface{}{"port": int64(80)}},
+		},
+		"status": map[string]interface{}{"loadBalancer": map[string]interface{}{}},
+	}}
+
+	clean(obj)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name":   "app",
+			"labels": map[string]interface{}{"app": "app"},
+		},
+		"spec": map[string]interface{}{
+			"ports": []interface{}{map[string]interface{}{"port": int64(80)}},
+		},
+	}, obj.Object)
+}
@@ -0,0 +1,161 @@
+package importrelease
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/fleetyaml"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// skipResources are created by controllers from the objects a user deploys,
+// e.g. endpoints copy the labels of their service.
+var skipResources = sets.NewString(
+	"events",
+	"events.events.k8s.io",
+	"endpoints",
+	"endpointslices.discovery.k8s.io",
+	"controllerrevisions.apps",
+	"leases.coordination.k8s.io",
+)
+
+// annotations set by kubernetes, which would be copied into the bundle
+var skipAnnotations = []string{
+	corev1.LastAppliedConfigAnnotation,
+	"deployment.kubernetes.io/revision",
+}
+
+// fromObjects writes the objects matching the selector into dir and returns
+// the fleet.yaml deploying them as a new release. Objects owned by other
+// objects are skipped, they are recreated by their owner. Secrets are
+// skipped, as their data doesn't belong into git.
+func fromObjects(ctx context.Context, getter genericclioptions.RESTClientGetter, name, dir string, opts *Options) (*fleetyaml.FleetYAML, error) {
+	discoveryClient, err := getter.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := namespacedResources(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, gvr := range resources {
+		list, err := client.Resource(gvr).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{LabelSelector: opts.Selector})
+		if err != nil {
+			logrus.Warnf("listing %s: %v", gvr.GroupResource(), err)
+			continue
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if len(obj.GetOwnerReferences()) > 0 {
+				continue
+			}
+			if gvr.Group == "" && gvr.Resource == "secrets" {
+				logrus.Warnf("skipping secret %s, create it separately or reference it with valuesFrom", obj.GetName())
+				continue
+			}
+
+			clean(obj)
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return nil, err
+			}
+			filename := fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+			if err := os.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+				return nil, err
+			}
+			count++
+		}
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("no objects matching %q found in namespace %s", opts.Selector, opts.Namespace)
+	}
+
+	logrus.Infof("imported %d objects matching %q from namespace %s", count, opts.Selector, opts.Namespace)
+	return newFleetYAML(opts.Namespace, &fleet.HelmOptions{
+		ReleaseName:   name,
+		TakeOwnership: opts.TakeOwnership,
+	}), nil
+}
+
+// namespacedResources returns the preferred version of the namespaced
+// resources which can be listed, sorted for a stable output.
+func namespacedResources(client discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
+	lists, err := client.ServerPreferredNamespacedResources()
+	if err != nil && len(lists) == 0 {
+		return nil, err
+	} else if err != nil {
+		logrus.Warnf("discovering resources: %v", err)
+	}
+
+	var result []schema.GroupVersionResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range list.APIResources {
+			gvr := gv.WithResource(r.Name)
+			if strings.Contains(r.Name, "/") || skipResources.Has(gvr.GroupResource().String()) ||
+				!sets.NewString(r.Verbs...).Has("list") {
+				continue
+			}
+			result = append(result, gvr)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result, nil
+}
+
+// clean removes the fields set by the cluster, so the object can be deployed
+// by fleet. The namespace is removed, it's set by defaultNamespace.
+func clean(obj *unstructured.Unstructured) {
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "namespace"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		for _, k := range skipAnnotations {
+			delete(annotations, k)
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+	}
+
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Service" {
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+	}
+}
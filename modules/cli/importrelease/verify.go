@@ -0,0 +1,123 @@
+package importrelease
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rancher/fleet/modules/cli/deploy"
+	"github.com/rancher/fleet/pkg/helmdeployer"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	actionAdopt  = "adopt"
+	actionCreate = "create"
+)
+
+// Change is what the first deployment of the bundle does to a resource.
+type Change struct {
+	Action    string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// verify deploys the bundle in dir with a dry run through the deployer and
+// prints which resources the release adopts and which it creates.
+func verify(ctx context.Context, getter genericclioptions.RESTClientGetter, name, dir string, opts *Options) error {
+	resources, err := deploy.DryRun(ctx, dir, &deploy.Options{
+		Kubeconfig:     opts.Kubeconfig,
+		Context:        opts.Context,
+		Name:           name,
+		AgentNamespace: opts.AgentNamespace,
+	})
+	if err != nil {
+		if !opts.TakeOwnership {
+			return fmt.Errorf("dry run failed, consider enabling takeOwnership: %w", err)
+		}
+		return fmt.Errorf("dry run failed: %w", err)
+	}
+
+	changes, err := diff(ctx, getter, resources)
+	if err != nil {
+		return err
+	}
+
+	created := 0
+	fmt.Fprintf(opts.Output, "# verified with a dry run of release %s\n", resources.ID)
+	for _, c := range changes {
+		if c.Action == actionCreate {
+			created++
+		}
+		fmt.Fprintf(opts.Output, "%-7s %s %s\n", c.Action, c.Kind, objectKey(c.Namespace, c.Name))
+	}
+	if created > 0 {
+		fmt.Fprintf(opts.Output, "# %d resources don't exist and will be created\n", created)
+	}
+	return nil
+}
+
+// diff looks up the resources of the release in the cluster, existing
+// resources are adopted.
+func diff(ctx context.Context, getter genericclioptions.RESTClientGetter, resources *helmdeployer.Resources) ([]Change, error) {
+	mapper, err := getter.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Change
+	for _, obj := range resources.Objects {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		change := Change{Action: actionAdopt, Kind: gvk.Kind, Name: m.GetName()}
+		var ri dynamic.ResourceInterface = client.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			change.Namespace = m.GetNamespace()
+			if change.Namespace == "" {
+				change.Namespace = resources.DefaultNamespace
+			}
+			ri = client.Resource(mapping.Resource).Namespace(change.Namespace)
+		}
+
+		if _, err := ri.Get(ctx, change.Name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			change.Action = actionCreate
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Action < result[j].Action
+	})
+	return result, nil
+}
+
+func objectKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
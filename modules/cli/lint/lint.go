@@ -0,0 +1,243 @@
+// Package lint validates the fleet.yaml files of a git repository, without
+// access to a Fleet Manager. (fleetlint)
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rancher/fleet/modules/cli/apply"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/fleetyaml"
+	"github.com/rancher/fleet/pkg/target"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// Problem is an issue found in a fleet.yaml file.
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+type bundleDir struct {
+	path  string
+	file  string
+	name  string
+	fleet *fleetyaml.FleetYAML
+}
+
+// Lint checks the fleet.yaml files in the bundle directories of baseDirs, as
+// found by 'fleet apply'. Unknown fields are reported, as well as invalid
+// label selectors, missing values files, duplicate target names, invalid
+// rollout limits and dependencies on bundles not found in the repo. If
+// repoName is empty, dependencies only have to match a bundle name suffix.
+func Lint(repoName string, baseDirs []string) ([]Problem, error) {
+	if len(baseDirs) == 0 {
+		baseDirs = []string{"."}
+	}
+
+	paths, err := apply.BundleDirs(baseDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		problems []Problem
+		dirs     []bundleDir
+	)
+	for _, path := range paths {
+		file, data, err := readFleetYAML(path)
+		if err != nil {
+			return nil, err
+		}
+		fy := &fleetyaml.FleetYAML{}
+		if file != "" {
+			var fileProblems []Problem
+			fy, fileProblems = Decode(file, data)
+			problems = append(problems, fileProblems...)
+			if fy == nil {
+				continue
+			}
+		}
+
+		name := apply.BundleName(repoName, path)
+		if fy.Name != "" {
+			name = fy.Name
+		}
+		dirs = append(dirs, bundleDir{path: path, file: file, name: name, fleet: fy})
+	}
+
+	for _, dir := range dirs {
+		problems = append(problems, check(dir)...)
+		problems = append(problems, checkDependsOn(repoName, dir, dirs)...)
+	}
+
+	return problems, nil
+}
+
+// Decode reads a fleet.yaml strictly, unknown and duplicate fields are
+// reported as problems. The content is returned if it can be read at all.
+func Decode(file string, data []byte) (*fleetyaml.FleetYAML, []Problem) {
+	fy := &fleetyaml.FleetYAML{}
+	err := yaml.UnmarshalStrict(data, fy)
+	if err == nil {
+		return fy, nil
+	}
+
+	msg := strings.TrimPrefix(err.Error(), "error unmarshaling JSON: while decoding JSON: ")
+	problems := []Problem{{Path: file, Message: msg}}
+
+	fy = &fleetyaml.FleetYAML{}
+	if err := yaml.Unmarshal(data, fy); err != nil {
+		return nil, problems
+	}
+	return fy, problems
+}
+
+func readFleetYAML(dir string) (string, []byte, error) {
+	for _, fallback := range []bool{false, true} {
+		file := fleetyaml.GetFleetYamlPath(dir, fallback)
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", nil, err
+		}
+		return file, data, nil
+	}
+	return "", nil, nil
+}
+
+func check(dir bundleDir) []Problem {
+	var (
+		problems []Problem
+		fy       = dir.fleet
+	)
+
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: dir.file, Message: fmt.Sprintf(format, args...)})
+	}
+
+	selector := func(field string, sel *metav1.LabelSelector) {
+		if sel == nil {
+			return
+		}
+		if _, err := metav1.LabelSelectorAsSelector(sel); err != nil {
+			report("invalid label selector %s: %v", field, err)
+		}
+	}
+
+	valuesFiles := func(field string, helm *fleet.HelmOptions) {
+		if helm == nil {
+			return
+		}
+		for _, file := range helm.ValuesFiles {
+			if _, err := os.Stat(filepath.Join(dir.path, file)); err != nil {
+				report("%s: values file %s not found", field, file)
+			}
+		}
+	}
+
+	limit := func(field string, val *intstr.IntOrString) {
+		if val == nil {
+			return
+		}
+		if err := target.ValidateLimit(val); err != nil {
+			report("%s: %v", field, err)
+		}
+	}
+
+	valuesFiles("helm", fy.Helm)
+
+	names := map[string]string{}
+	checkTarget := func(field string, t fleet.BundleTarget) {
+		selector(field+".clusterSelector", t.ClusterSelector)
+		selector(field+".clusterGroupSelector", t.ClusterGroupSelector)
+		valuesFiles(field+".helm", t.Helm)
+		if t.Name == "" {
+			return
+		}
+		if prev, ok := names[t.Name]; ok {
+			report("%s: duplicate target name %q, already used by %s", field, t.Name, prev)
+			return
+		}
+		names[t.Name] = field
+	}
+	for i, t := range fy.Targets {
+		checkTarget(fmt.Sprintf("targets[%d]", i), t)
+	}
+	for i, t := range fy.TargetCustomizations {
+		checkTarget(fmt.Sprintf("targetCustomizations[%d]", i), t)
+	}
+
+	for i, r := range fy.TargetRestrictions {
+		field := fmt.Sprintf("targetRestrictions[%d]", i)
+		selector(field+".clusterSelector", r.ClusterSelector)
+		selector(field+".clusterGroupSelector", r.ClusterGroupSelector)
+	}
+
+	if rollout := fy.RolloutStrategy; rollout != nil {
+		limit("rolloutStrategy.maxUnavailable", rollout.MaxUnavailable)
+		limit("rolloutStrategy.maxUnavailablePartitions", rollout.MaxUnavailablePartitions)
+		limit("rolloutStrategy.autoPartitionSize", rollout.AutoPartitionSize)
+		for i, p := range rollout.Partitions {
+			field := fmt.Sprintf("rolloutStrategy.partitions[%d]", i)
+			limit(field+".maxUnavailable", p.MaxUnavailable)
+			selector(field+".clusterSelector", p.ClusterSelector)
+			selector(field+".clusterGroupSelector", p.ClusterGroupSelector)
+		}
+	}
+
+	for i, dep := range fy.DependsOn {
+		selector(fmt.Sprintf("dependsOn[%d].selector", i), dep.Selector)
+	}
+
+	return problems
+}
+
+func checkDependsOn(repoName string, dir bundleDir, dirs []bundleDir) []Problem {
+	var problems []Problem
+	for i, dep := range dir.fleet.DependsOn {
+		if dep.Name == "" || dependencyFound(repoName, dep.Name, dirs) {
+			continue
+		}
+		problems = append(problems, Problem{
+			Path:    dir.file,
+			Message: fmt.Sprintf("dependsOn[%d]: no bundle named %q in %s", i, dep.Name, bundleNames(dirs)),
+		})
+	}
+	return problems
+}
+
+// dependencyFound returns true if name matches one of the bundles. Without a
+// repo name, the bundle names are only known up to the repo name prefix.
+func dependencyFound(repoName, name string, dirs []bundleDir) bool {
+	for _, dir := range dirs {
+		if dir.name == name {
+			return true
+		}
+		if repoName == "" && dir.fleet.Name == "" && strings.HasSuffix(name, "-"+dir.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func bundleNames(dirs []bundleDir) string {
+	var names []string
+	for _, dir := range dirs {
+		names = append(names, dir.name)
+	}
+	sort.Strings(names)
+	return "[" + strings.Join(names, ", ") + "]"
+}
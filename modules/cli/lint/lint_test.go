@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validFleetYAML = `
+defaultNamespace: app
+helm:
+  valuesFiles:
+  - values.yaml
+  values:
+    any:
+      nested: value
+rolloutStrategy:
+  maxUnavailable: 10%
+  autoPartitionSize: 5
+targetCustomizations:
+- name: prod
+  clusterSelector:
+    matchLabels:
+      env: prod
+dependsOn:
+- name: repo-db
+`
+
+const invalidFleetYAML = `
+helm:
+  valuesFiles:
+  - missing.yaml
+rolloutStrategy:
+  maxUnavailable: half
+targets:
+- name: prod
+  clusterSelector:
+    matchExpressions:
+    - key: env
+      operator: Unknown
+targetCustomizations:
+- name: prod
+dependsOn:
+- name: repo-cache
+unknown: true
+`
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func lint(t *testing.T, repoName string, files map[string]string) []string {
+	dir := writeFiles(t, files)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	problems, err := Lint(repoName, []string{"."})
+	require.NoError(t, err)
+
+	var result []string
+	for _, p := range problems {
+		result = append(result, p.String())
+	}
+	return result
+}
+
+func TestLintValid(t *testing.T) {
+	files := map[string]string{
+		"app/fleet.yaml":  validFleetYAML,
+		"app/values.yaml": "replicas: 1\n",
+		"db/fleet.yaml":   "defaultNamespace: db\n",
+	}
+	assert.Empty(t, lint(t, "repo", files))
+	assert.Empty(t, lint(t, "", files))
+}
+
+func TestLintInvalid(t *testing.T) {
+	problems := lint(t, "repo", map[string]string{
+		"app/fleet.yaml": invalidFleetYAML,
+		"db/fleet.yaml":  "defaultNamespace: db\n",
+	})
+
+	assert.Equal(t, []string{
+		`app/fleet.yaml: json: unknown field "unknown"`,
+		`app/fleet.yaml: helm: values file missing.yaml not found`,
+		`app/fleet.yaml: invalid label selector targets[0].clusterSelector: "Unknown" is not a valid pod selector operator`,
+		`app/fleet.yaml: targetCustomizations[0]: duplicate target name "prod", already used by targets[0]`,
+		`app/fleet.yaml: rolloutStrategy.maxUnavailable: invalid maxUnavailable, must be int or percentage (ending with %): half`,
+		`app/fleet.yaml: dependsOn[0]: no bundle named "repo-cache" in [repo, repo-app, repo-db]`,
+	}, problems)
+}
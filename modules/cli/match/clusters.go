@@ -0,0 +1,238 @@
+package match
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlematcher"
+	"github.com/rancher/fleet/pkg/helmdeployer"
+	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/target"
+
+	"github.com/rancher/wrangler/pkg/yaml"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// ClustersFromFile reads Cluster and ClusterGroup objects from a YAML file,
+// e.g. exported with 'kubectl get clusters,clustergroups -o yaml'. Other
+// kinds are ignored.
+func ClustersFromFile(path string) ([]*fleet.Cluster, []*fleet.ClusterGroup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	objs, err := yaml.ToObjects(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		clusters      []*fleet.Cluster
+		clusterGroups []*fleet.ClusterGroup
+	)
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		switch u.GetObjectKind().GroupVersionKind() {
+		case fleet.SchemeGroupVersion.WithKind("Cluster"):
+			cluster := &fleet.Cluster{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cluster); err != nil {
+				return nil, nil, err
+			}
+			clusters = append(clusters, cluster)
+		case fleet.SchemeGroupVersion.WithKind("ClusterGroup"):
+			clusterGroup := &fleet.ClusterGroup{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, clusterGroup); err != nil {
+				return nil, nil, err
+			}
+			clusterGroups = append(clusterGroups, clusterGroup)
+		}
+	}
+
+	if len(clusters) == 0 {
+		return nil, nil, fmt.Errorf("no clusters found in %s", path)
+	}
+	return clusters, clusterGroups, nil
+}
+
+// ClustersFromManager lists the Cluster and ClusterGroup objects in the
+// namespace of the client.
+func ClustersFromManager(client *client.Getter) ([]*fleet.Cluster, []*fleet.ClusterGroup, error) {
+	c, err := client.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clusterList, err := c.Fleet.Cluster().List(c.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterGroupList, err := c.Fleet.ClusterGroup().List(c.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		clusters      []*fleet.Cluster
+		clusterGroups []*fleet.ClusterGroup
+	)
+	for i := range clusterList.Items {
+		clusters = append(clusters, &clusterList.Items[i])
+	}
+	for i := range clusterGroupList.Items {
+		clusterGroups = append(clusterGroups, &clusterGroupList.Items[i])
+	}
+
+	if len(clusters) == 0 {
+		return nil, nil, fmt.Errorf("no clusters found in namespace %s", c.Namespace)
+	}
+	return clusters, clusterGroups, nil
+}
+
+// clusterMatch is the result of matching the bundle against one cluster.
+type clusterMatch struct {
+	cluster      *fleet.Cluster
+	groups       []string
+	target       *fleet.BundleTarget
+	restricted   bool
+	options      fleet.BundleDeploymentOptions
+	deploymentID string
+}
+
+func (m clusterMatch) result() string {
+	switch {
+	case m.target != nil:
+		return "matched"
+	case m.restricted:
+		return "blocked by targetRestrictions"
+	}
+	return "no target matched"
+}
+
+// matchClusters matches the bundle against every cluster like the
+// fleet-controller does, including the substitution of cluster labels in
+// the helm values. It prints a table of the results to opts.Matrix and
+// the options and rendered resources per matched cluster to opts.Output.
+func matchClusters(bundle *fleet.Bundle, bm *bundlematcher.BundleMatch, opts *Options) error {
+	m, err := manifest.New(bundle.Spec.Resources)
+	if err != nil {
+		return err
+	}
+
+	clusters := append([]*fleet.Cluster{}, opts.Clusters...)
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	var matches []clusterMatch
+	for _, cluster := range clusters {
+		var cgs []*fleet.ClusterGroup
+		for _, cg := range opts.ClusterGroups {
+			if cg.Namespace == cluster.Namespace {
+				cgs = append(cgs, cg)
+			}
+		}
+		cgs = target.ClusterGroupsForCluster(cluster, cgs)
+		groups := target.ClusterGroupLabels(cgs)
+
+		result := clusterMatch{
+			cluster: cluster,
+			target:  bm.Match(cluster.Name, groups, cluster.Labels),
+		}
+		for _, cg := range cgs {
+			result.groups = append(result.groups, cg.Name)
+		}
+		sort.Strings(result.groups)
+
+		if result.target == nil {
+			result.restricted = bm.IsRestricted(cluster.Name, groups, cluster.Labels)
+		} else {
+			result.options, result.deploymentID, err = target.DeploymentOptions(bundle, m, result.target, cluster.Labels)
+			if err != nil {
+				return fmt.Errorf("cluster %s/%s: %w", cluster.Namespace, cluster.Name, err)
+			}
+		}
+		matches = append(matches, result)
+	}
+
+	matrix := opts.Matrix
+	if matrix == nil {
+		matrix = os.Stderr
+	}
+	if err := printMatrix(matrix, matches); err != nil {
+		return err
+	}
+
+	if opts.Output == nil {
+		return nil
+	}
+	for _, match := range matches {
+		if match.target == nil {
+			continue
+		}
+		if err := printClusterMatch(opts.Output, bundle, m, match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printMatrix(out io.Writer, matches []clusterMatch) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tCLUSTER-GROUPS\tTARGET\tRESULT")
+	for _, m := range matches {
+		targetName := "-"
+		if m.target != nil {
+			targetName = m.target.Name
+		}
+		groups := "-"
+		if len(m.groups) > 0 {
+			groups = strings.Join(m.groups, ",")
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", m.cluster.Namespace, m.cluster.Name, groups, targetName, m.result())
+	}
+	return w.Flush()
+}
+
+// printClusterMatch prints the merged options of the cluster as comment,
+// followed by the rendered resources.
+func printClusterMatch(out io.Writer, bundle *fleet.Bundle, m *manifest.Manifest, match clusterMatch) error {
+	optionsData, err := sigsyaml.Marshal(match.options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "---\n# Cluster: %s/%s\n# Target: %s\n# DeploymentID: %s\n# Options:\n",
+		match.cluster.Namespace, match.cluster.Name, match.target.Name, match.deploymentID)
+	for _, line := range strings.Split(strings.TrimSpace(string(optionsData)), "\n") {
+		fmt.Fprintf(out, "#   %s\n", line)
+	}
+
+	objs, err := helmdeployer.Template(bundle.Name, m, match.options)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Export(objs...)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
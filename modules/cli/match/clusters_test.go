@@ -0,0 +1,196 @@
+package match
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlematcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const clustersYAML = `apiVersion: v1
+kind: List
+items:
+- apiVersion: fleet.cattle.io/v1alpha1
+  kind: Cluster
+  metadata:
+    name: prod
+    namespace: fleet-default
+    labels:
+      env: prod
+- apiVersion: fleet.cattle.io/v1alpha1
+  kind: Cluster
+  metadata:
+    name: dev
+    namespace: fleet-default
+    labels:
+      env: dev
+- apiVersion: fleet.cattle.io/v1alpha1
+  kind: Cluster
+  metadata:
+    name: staging
+    namespace: fleet-default
+    labels:
+      env: staging
+- apiVersion: fleet.cattle.io/v1alpha1
+  kind: ClusterGroup
+  metadata:
+    name: dev
+    namespace: fleet-default
+  spec:
+    selector:
+      matchLabels:
+        env: dev
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: ignored
+`
+
+func writeClusters(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func testBundle() *fleet.Bundle {
+	return &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: fleet.BundleSpec{
+			BundleDeploymentOptions: fleet.BundleDeploymentOptions{DefaultNamespace: "app"},
+			Resources: []fleet.BundleResource{{
+				Name:    "cm.yaml",
+				Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+			}},
+			Targets: []fleet.BundleTarget{
+				{
+					Name:                    "prod",
+					ClusterSelector:         &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					BundleDeploymentOptions: fleet.BundleDeploymentOptions{TargetNamespace: "app-prod"},
+				},
+				{Name: "dev", ClusterGroup: "dev"},
+			},
+		},
+	}
+}
+
+func TestClustersFromFile(t *testing.T) {
+	clusters, clusterGroups, err := ClustersFromFile(writeClusters(t, clustersYAML))
+	require.NoError(t, err)
+	require.Len(t, clusters, 3)
+	require.Len(t, clusterGroups, 1)
+	assert.Equal(t, "prod", clusters[0].Name)
+	assert.Equal(t, "prod", clusters[0].Labels["env"])
+	assert.Equal(t, "dev", clusterGroups[0].Spec.Selector.MatchLabels["env"])
+
+	_, _, err = ClustersFromFile(writeClusters(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))
+	assert.Error(t, err)
+
+	_, _, err = ClustersFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestMatchClusters(t *testing.T) {
+	clusters, clusterGroups, err := ClustersFromFile(writeClusters(t, clustersYAML))
+	require.NoError(t, err)
+
+	bundle := testBundle()
+	bm, err := bundlematcher.New(bundle)
+	require.NoError(t, err)
+
+	matrix, output := &bytes.Buffer{}, &bytes.Buffer{}
+	err = matchClusters(bundle, bm, &Options{
+		Clusters:      clusters,
+		ClusterGroups: clusterGroups,
+		Matrix:        matrix,
+		Output:        output,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `CLUSTER                CLUSTER-GROUPS  TARGET  RESULT
+fleet-default/dev      dev             dev     matched
+fleet-default/prod     -               prod    matched
+fleet-default/staging  -               -       no target matched
+`, matrix.String())
+	assert.Contains(t, output.String(), "# Cluster: fleet-default/prod\n# Target: prod\n")
+	assert.Contains(t, output.String(), "#   namespace: app-prod\n")
+	assert.Contains(t, output.String(), "# Cluster: fleet-default/dev\n# Target: dev\n")
+	assert.NotContains(t, output.String(), "fleet-default/staging")
+}
+
+func TestMatchClustersRestricted(t *testing.T) {
+	clusters, clusterGroups, err := ClustersFromFile(writeClusters(t, clustersYAML))
+	require.NoError(t, err)
+
+	// the GitRepo only allows the prod cluster
+	bundle := testBundle()
+	bundle.Spec.TargetRestrictions = []fleet.BundleTargetRestriction{
+		{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+	}
+	bm, err := bundlematcher.New(bundle)
+	require.NoError(t, err)
+	assert.True(t, bm.IsRestricted("dev", map[string]map[string]string{"dev": nil}, map[string]string{"env": "dev"}))
+	assert.False(t, bm.IsRestricted("prod", nil, map[string]string{"env": "prod"}))
+	// no target selects staging, the restrictions don't block it
+	assert.False(t, bm.IsRestricted("staging", nil, map[string]string{"env": "staging"}))
+
+	matrix := &bytes.Buffer{}
+	err = matchClusters(bundle, bm, &Options{
+		Clusters:      clusters,
+		ClusterGroups: clusterGroups,
+		Matrix:        matrix,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `CLUSTER                CLUSTER-GROUPS  TARGET  RESULT
+fleet-default/dev      dev             -       blocked by targetRestrictions
+fleet-default/prod     -               prod    matched
+fleet-default/staging  -               -       no target matched
+`, matrix.String())
+
+	// GitRepo bundles copy their targets into the restrictions, clusters
+	// without a target aren't blocked
+	bundle = testBundle()
+	for _, target := range bundle.Spec.Targets {
+		bundle.Spec.TargetRestrictions = append(bundle.Spec.TargetRestrictions, fleet.BundleTargetRestriction{
+			Name:            target.Name,
+			ClusterSelector: target.ClusterSelector,
+			ClusterGroup:    target.ClusterGroup,
+		})
+	}
+	bm, err = bundlematcher.New(bundle)
+	require.NoError(t, err)
+
+	matrix = &bytes.Buffer{}
+	err = matchClusters(bundle, bm, &Options{
+		Clusters:      clusters,
+		ClusterGroups: clusterGroups,
+		Matrix:        matrix,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `CLUSTER                CLUSTER-GROUPS  TARGET  RESULT
+fleet-default/dev      dev             dev     matched
+fleet-default/prod     -               prod    matched
+fleet-default/staging  -               -       no target matched
+`, matrix.String())
+}
+
+func TestPrintMatrix(t *testing.T) {
+	cluster := &fleet.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "local"}}
+	out := &bytes.Buffer{}
+	err := printMatrix(out, []clusterMatch{
+		{cluster: cluster, groups: []string{"a", "b"}, target: &fleet.BundleTarget{Name: "default"}},
+		{cluster: cluster, restricted: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `CLUSTER            CLUSTER-GROUPS  TARGET   RESULT
+fleet-local/local  a,b             default  matched
+fleet-local/local  -               -        blocked by targetRestrictions
+`, out.String())
+}
@@ -32,6 +32,13 @@ type Options struct {
 	ClusterLabels      map[string]string
 	ClusterGroupLabels map[string]string
 	Target             string
+	// Clusters and ClusterGroups replace the synthetic cluster given by
+	// the options above, the bundle is matched against every cluster
+	Clusters      []*fleet.Cluster
+	ClusterGroups []*fleet.ClusterGroup
+	// Matrix receives the table of matched targets, when matching against
+	// Clusters
+	Matrix io.Writer
 }
 
 func Match(ctx context.Context, opts *Options) error {
@@ -39,26 +46,9 @@ func Match(ctx context.Context, opts *Options) error {
 		opts = &Options{}
 	}
 
-	var (
-		bundle *fleet.Bundle
-		err    error
-	)
-
-	if opts.BundleFile == "" {
-		bundle, _, err = bundlereader.Open(ctx, "test", opts.BaseDir, opts.BundleSpec, nil)
-		if err != nil {
-			return err
-		}
-	} else {
-		data, err := os.ReadFile(opts.BundleFile)
-		if err != nil {
-			return err
-		}
-
-		bundle = &fleet.Bundle{}
-		if err := yaml.Unmarshal(data, bundle); err != nil {
-			return err
-		}
+	bundle, err := readBundle(ctx, opts)
+	if err != nil {
+		return err
 	}
 
 	bm, err := bundlematcher.New(bundle)
@@ -66,6 +56,10 @@ func Match(ctx context.Context, opts *Options) error {
 		return err
 	}
 
+	if len(opts.Clusters) > 0 {
+		return matchClusters(bundle, bm, opts)
+	}
+
 	if opts.Target == "" {
 		m := bm.Match(opts.ClusterName, map[string]map[string]string{
 			opts.ClusterGroup: opts.ClusterGroupLabels,
@@ -76,6 +70,24 @@ func Match(ctx context.Context, opts *Options) error {
 	return printMatch(bundle, bm.MatchForTarget(opts.Target), opts.Output)
 }
 
+func readBundle(ctx context.Context, opts *Options) (*fleet.Bundle, error) {
+	if opts.BundleFile == "" {
+		bundle, _, err := bundlereader.Open(ctx, "test", opts.BaseDir, opts.BundleSpec, nil)
+		return bundle, err
+	}
+
+	data, err := os.ReadFile(opts.BundleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &fleet.Bundle{}
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
 func printMatch(bundle *fleet.Bundle, target *fleet.BundleTarget, output io.Writer) error {
 	if target == nil {
 		return errors.New("no match found")
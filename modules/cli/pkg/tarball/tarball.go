@@ -0,0 +1,88 @@
+// Package tarball writes gzip compressed tar archives. (fleetapply)
+package tarball
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// Writer adds files to a tar.gz archive.
+type Writer struct {
+	f  *os.File
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+// Create creates the archive file.
+func Create(filename string) (*Writer, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	return &Writer{f: f, gz: gz, tw: tar.NewWriter(gz)}, nil
+}
+
+// Add adds a file with the data to the archive.
+func (w *Writer) Add(name string, data []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+// AddFile adds the content of a local file to the archive.
+func (w *Writer) AddFile(name, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tw, f)
+	return err
+}
+
+// AddJSON adds a file with the indented JSON of obj to the archive.
+func (w *Writer) AddJSON(name string, obj interface{}) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.Add(name, data)
+}
+
+// Close flushes the archive and closes the file.
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		w.f.Close()
+		return err
+	}
+	if err := w.gz.Close(); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
@@ -0,0 +1,51 @@
+package tarball
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "image.tar")
+	require.NoError(t, os.WriteFile(local, []byte("image"), 0644))
+
+	filename := filepath.Join(dir, "archive.tar.gz")
+	w, err := Create(filename)
+	require.NoError(t, err)
+	require.NoError(t, w.Add("summary.txt", []byte("summary")))
+	require.NoError(t, w.AddFile("images/image.tar", local))
+	require.NoError(t, w.AddJSON("manifest.json", map[string]int{"version": 1}))
+	require.NoError(t, w.Close())
+
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	assert.Equal(t, map[string]string{
+		"summary.txt":      "summary",
+		"images/image.tar": "image",
+		"manifest.json":    "{\n  \"version\": 1\n}",
+	}, files)
+}
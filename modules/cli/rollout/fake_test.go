@@ -0,0 +1,135 @@
+package rollout
+
+import (
+	"sort"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeFleet serves the fleet resources used by the rollout actions from
+// memory.
+type fakeFleet struct {
+	fleetcontrollers.Interface
+	bundles       []fleet.Bundle
+	clusters      []fleet.Cluster
+	clusterGroups []fleet.ClusterGroup
+	deployments   map[string]*fleet.BundleDeployment
+	updates       int
+}
+
+func (f *fakeFleet) Bundle() fleetcontrollers.BundleController {
+	return fakeBundles{fleet: f}
+}
+
+func (f *fakeFleet) Cluster() fleetcontrollers.ClusterController {
+	return fakeClusters{fleet: f}
+}
+
+func (f *fakeFleet) ClusterGroup() fleetcontrollers.ClusterGroupController {
+	return fakeClusterGroups{fleet: f}
+}
+
+func (f *fakeFleet) BundleDeployment() fleetcontrollers.BundleDeploymentController {
+	return fakeBundleDeployments{fleet: f}
+}
+
+func selector(opts metav1.ListOptions) (labels.Selector, error) {
+	return labels.Parse(opts.LabelSelector)
+}
+
+type fakeBundles struct {
+	fleetcontrollers.BundleController
+	fleet *fakeFleet
+}
+
+func (b fakeBundles) List(namespace string, opts metav1.ListOptions) (*fleet.BundleList, error) {
+	sel, err := selector(opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &fleet.BundleList{}
+	for _, bundle := range b.fleet.bundles {
+		if bundle.Namespace == namespace && sel.Matches(labels.Set(bundle.Labels)) {
+			result.Items = append(result.Items, bundle)
+		}
+	}
+	return result, nil
+}
+
+type fakeClusters struct {
+	fleetcontrollers.ClusterController
+	fleet *fakeFleet
+}
+
+func (c fakeClusters) List(namespace string, opts metav1.ListOptions) (*fleet.ClusterList, error) {
+	sel, err := selector(opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &fleet.ClusterList{}
+	for _, cluster := range c.fleet.clusters {
+		if cluster.Namespace == namespace && sel.Matches(labels.Set(cluster.Labels)) {
+			result.Items = append(result.Items, cluster)
+		}
+	}
+	return result, nil
+}
+
+type fakeClusterGroups struct {
+	fleetcontrollers.ClusterGroupController
+	fleet *fakeFleet
+}
+
+func (c fakeClusterGroups) Get(namespace, name string, opts metav1.GetOptions) (*fleet.ClusterGroup, error) {
+	for _, cg := range c.fleet.clusterGroups {
+		if cg.Namespace == namespace && cg.Name == name {
+			return cg.DeepCopy(), nil
+		}
+	}
+	return nil, apierrors.NewNotFound(fleet.Resource("clustergroup"), name)
+}
+
+type fakeBundleDeployments struct {
+	fleetcontrollers.BundleDeploymentController
+	fleet *fakeFleet
+}
+
+func (b fakeBundleDeployments) Get(namespace, name string, opts metav1.GetOptions) (*fleet.BundleDeployment, error) {
+	bd, ok := b.fleet.deployments[namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(fleet.Resource("bundledeployment"), name)
+	}
+	return bd.DeepCopy(), nil
+}
+
+func (b fakeBundleDeployments) List(namespace string, opts metav1.ListOptions) (*fleet.BundleDeploymentList, error) {
+	sel, err := selector(opts)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(b.fleet.deployments))
+	for key := range b.fleet.deployments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := &fleet.BundleDeploymentList{}
+	for _, key := range keys {
+		bd := b.fleet.deployments[key]
+		if (namespace == "" || bd.Namespace == namespace) && sel.Matches(labels.Set(bd.Labels)) {
+			result.Items = append(result.Items, *bd.DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (b fakeBundleDeployments) Update(bd *fleet.BundleDeployment) (*fleet.BundleDeployment, error) {
+	b.fleet.deployments[bd.Namespace+"/"+bd.Name] = bd.DeepCopy()
+	b.fleet.updates++
+	return bd, nil
+}
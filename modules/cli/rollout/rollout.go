@@ -0,0 +1,374 @@
+// Package rollout pauses, resumes, promotes and redeploys bundles on the
+// Fleet Manager. (fleetrollout)
+package rollout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/target"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	ActionPause    = "pause"
+	ActionResume   = "resume"
+	ActionPromote  = "promote"
+	ActionRedeploy = "redeploy"
+
+	// maxAuditEntries limits the number of actions kept in the audit
+	// annotation of a resource.
+	maxAuditEntries = 10
+)
+
+var done = map[string]string{
+	ActionPause:    "paused",
+	ActionResume:   "resumed",
+	ActionPromote:  "promoted",
+	ActionRedeploy: "redeployed",
+}
+
+var ErrNoScope = errors.New("one of --gitrepo, --bundle, --cluster, --cluster-group or --selector is required")
+
+// Scope selects the resources an action applies to. GitRepo and Bundle select
+// bundles, Cluster, ClusterGroup and ClusterSelector select clusters.
+type Scope struct {
+	GitRepo         string
+	Bundle          string
+	Cluster         string
+	ClusterGroup    string
+	ClusterSelector string
+}
+
+func (s Scope) bundles() bool {
+	return s.GitRepo != "" || s.Bundle != ""
+}
+
+func (s Scope) clusters() bool {
+	return s.Cluster != "" || s.ClusterGroup != "" || s.ClusterSelector != ""
+}
+
+type Options struct {
+	Scope
+	Output io.Writer
+	Reason string
+}
+
+// AuditEntry is an action recorded in the RolloutAuditAnnotation.
+type AuditEntry struct {
+	Action string    `json:"action"`
+	User   string    `json:"user,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// Pause pauses the GitRepo, the Bundle or the selected clusters. Paused
+// bundle deployments keep their current deployment until resumed.
+func Pause(client *client.Getter, opts *Options) error {
+	return setPaused(client, ActionPause, true, opts)
+}
+
+// Resume reverts Pause.
+func Resume(client *client.Getter, opts *Options) error {
+	return setPaused(client, ActionResume, false, opts)
+}
+
+func setPaused(client *client.Getter, action string, paused bool, opts *Options) error {
+	c, err := client.Get()
+	if err != nil {
+		return err
+	}
+
+	entry := newEntry(action, opts.Reason)
+
+	switch {
+	case opts.GitRepo != "":
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			gitrepo, err := c.Fleet.GitRepo().Get(c.Namespace, opts.GitRepo, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			gitrepo.Spec.Paused = paused
+			if err := audit(&gitrepo.ObjectMeta, entry); err != nil {
+				return err
+			}
+			if _, err := c.Fleet.GitRepo().Update(gitrepo); err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Output, "gitrepo %s/%s: %s\n", gitrepo.Namespace, gitrepo.Name, done[action])
+			return nil
+		})
+	case opts.Bundle != "":
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			bundle, err := c.Fleet.Bundle().Get(c.Namespace, opts.Bundle, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if repo := bundle.Labels[fleet.RepoLabel]; repo != "" {
+				logrus.Warnf("bundle %s is created by gitrepo %s, the next commit sets it to the gitrepo's paused state", bundle.Name, repo)
+			}
+			bundle.Spec.Paused = paused
+			if err := audit(&bundle.ObjectMeta, entry); err != nil {
+				return err
+			}
+			if _, err := c.Fleet.Bundle().Update(bundle); err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Output, "bundle %s/%s: %s\n", bundle.Namespace, bundle.Name, done[action])
+			return nil
+		})
+	case opts.clusters():
+		clusters, err := selectClusters(c, opts.Scope)
+		if err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				cluster, err := c.Fleet.Cluster().Get(cluster.Namespace, cluster.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				cluster.Spec.Paused = paused
+				if err := audit(&cluster.ObjectMeta, entry); err != nil {
+					return err
+				}
+				_, err = c.Fleet.Cluster().Update(cluster)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Output, "cluster %s/%s: %s\n", cluster.Namespace, cluster.Name, done[action])
+		}
+		return nil
+	}
+
+	return ErrNoScope
+}
+
+// Promote deploys the staged deployment of the selected bundle deployments,
+// regardless of the rollout strategy and of bundles or clusters being paused.
+func Promote(client *client.Getter, opts *Options) error {
+	return updateDeployments(client, ActionPromote, opts, promote)
+}
+
+// Redeploy makes the agents deploy the selected bundle deployments again, by
+// increasing their sync generation.
+func Redeploy(client *client.Getter, opts *Options) error {
+	return updateDeployments(client, ActionRedeploy, opts, redeploy)
+}
+
+func promote(bd *fleet.BundleDeployment) bool {
+	if bd.Spec.StagedDeploymentID == "" || bd.Spec.DeploymentID == bd.Spec.StagedDeploymentID {
+		return false
+	}
+	bd.Spec.DeploymentID = bd.Spec.StagedDeploymentID
+	bd.Spec.Options = bd.Spec.StagedOptions
+	return true
+}
+
+func redeploy(bd *fleet.BundleDeployment) bool {
+	generation := bd.Spec.Options.ForceSyncGeneration
+	if bd.Status.SyncGeneration != nil && *bd.Status.SyncGeneration > generation {
+		generation = *bd.Status.SyncGeneration
+	}
+	bd.Spec.Options.ForceSyncGeneration = generation + 1
+	return true
+}
+
+// updateDeployments calls update for the bundle deployments of the selected
+// bundles on the selected clusters, and saves them if update returns true.
+func updateDeployments(client *client.Getter, action string, opts *Options, update func(bd *fleet.BundleDeployment) bool) error {
+	if !opts.bundles() && !opts.clusters() {
+		return ErrNoScope
+	}
+
+	c, err := client.Get()
+	if err != nil {
+		return err
+	}
+	return updateBundleDeployments(c, action, opts, update)
+}
+
+func updateBundleDeployments(c *client.Client, action string, opts *Options, update func(bd *fleet.BundleDeployment) bool) error {
+	bundles, err := selectBundles(c, opts.Scope)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := selectClusters(c, opts.Scope)
+	if err != nil {
+		return err
+	}
+	clusterNamespaces := map[string]*fleet.Cluster{}
+	for _, cluster := range clusters {
+		if cluster.Status.Namespace != "" {
+			clusterNamespaces[cluster.Status.Namespace] = cluster
+		}
+	}
+
+	bds, err := c.Fleet.BundleDeployment().List("", metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"fleet.cattle.io/bundle-namespace": c.Namespace}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	entry := newEntry(action, opts.Reason)
+	count := 0
+	for _, bd := range bds.Items {
+		cluster, ok := clusterNamespaces[bd.Namespace]
+		if !ok || !bundles[bd.Labels["fleet.cattle.io/bundle-name"]] {
+			continue
+		}
+
+		updated := false
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			bd, err := c.Fleet.BundleDeployment().Get(bd.Namespace, bd.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if updated = update(bd); !updated {
+				return nil
+			}
+			if err := audit(&bd.ObjectMeta, entry); err != nil {
+				return err
+			}
+			_, err = c.Fleet.BundleDeployment().Update(bd)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if updated {
+			count++
+			fmt.Fprintf(opts.Output, "bundledeployment %s/%s (cluster %s): %s\n", bd.Namespace, bd.Name, cluster.Name, done[action])
+		}
+	}
+
+	if count == 0 {
+		fmt.Fprintf(opts.Output, "no bundle deployments to %s\n", action)
+	}
+	return nil
+}
+
+// selectBundles returns the names of the bundles in scope, all bundles if the
+// scope doesn't select bundles.
+func selectBundles(c *client.Client, scope Scope) (map[string]bool, error) {
+	opts := metav1.ListOptions{}
+	if scope.GitRepo != "" {
+		opts.LabelSelector = labels.SelectorFromSet(labels.Set{fleet.RepoLabel: scope.GitRepo}).String()
+	}
+
+	bundles, err := c.Fleet.Bundle().List(c.Namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]bool{}
+	for _, bundle := range bundles.Items {
+		if scope.Bundle != "" && bundle.Name != scope.Bundle {
+			continue
+		}
+		result[bundle.Name] = true
+	}
+	if len(result) == 0 && scope.bundles() {
+		return nil, fmt.Errorf("no bundles found for gitrepo %q, bundle %q", scope.GitRepo, scope.Bundle)
+	}
+	return result, nil
+}
+
+// selectClusters returns the clusters in scope, all clusters if the scope
+// doesn't select clusters.
+func selectClusters(c *client.Client, scope Scope) ([]*fleet.Cluster, error) {
+	sel := labels.Everything()
+	if scope.ClusterSelector != "" {
+		var err error
+		if sel, err = labels.Parse(scope.ClusterSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	clusters, err := c.Fleet.Cluster().List(c.Namespace, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var cgs []*fleet.ClusterGroup
+	if scope.ClusterGroup != "" {
+		cg, err := c.Fleet.ClusterGroup().Get(c.Namespace, scope.ClusterGroup, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		cgs = append(cgs, cg)
+	}
+
+	var result []*fleet.Cluster
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if scope.Cluster != "" && cluster.Name != scope.Cluster {
+			continue
+		}
+		if len(cgs) > 0 && len(target.ClusterGroupsForCluster(cluster, cgs)) == 0 {
+			continue
+		}
+		result = append(result, cluster)
+	}
+	if len(result) == 0 && scope.clusters() {
+		return nil, errors.New("no clusters matched")
+	}
+	return result, nil
+}
+
+func newEntry(action, reason string) AuditEntry {
+	entry := AuditEntry{
+		Action: action,
+		Reason: reason,
+		Time:   time.Now().UTC().Truncate(time.Second),
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	} else {
+		entry.User = os.Getenv("USER")
+	}
+	return entry
+}
+
+// audit appends entry to the RolloutAuditAnnotation of obj, dropping the
+// oldest entries.
+func audit(obj *metav1.ObjectMeta, entry AuditEntry) error {
+	var entries []AuditEntry
+	if data := obj.Annotations[fleet.RolloutAuditAnnotation]; data != "" {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			logrus.Warnf("resetting invalid %s annotation on %s/%s: %v", fleet.RolloutAuditAnnotation, obj.Namespace, obj.Name, err)
+			entries = nil
+		}
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > maxAuditEntries {
+		entries = entries[len(entries)-maxAuditEntries:]
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[fleet.RolloutAuditAnnotation] = string(data)
+	return nil
+}
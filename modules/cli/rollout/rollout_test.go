@@ -0,0 +1,157 @@
+package rollout
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAudit(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "bundle"}
+
+	for i := 0; i < maxAuditEntries+2; i++ {
+		entry := AuditEntry{Action: ActionRedeploy, User: "admin", Reason: fmt.Sprint(i), Time: time.Unix(int64(i), 0).UTC()}
+		require.NoError(t, audit(obj, entry))
+	}
+
+	var entries []AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(obj.Annotations[fleet.RolloutAuditAnnotation]), &entries))
+	require.Len(t, entries, maxAuditEntries)
+	assert.Equal(t, "2", entries[0].Reason)
+	assert.Equal(t, fmt.Sprint(maxAuditEntries+1), entries[maxAuditEntries-1].Reason)
+
+	obj.Annotations[fleet.RolloutAuditAnnotation] = "invalid"
+	require.NoError(t, audit(obj, AuditEntry{Action: ActionPause}))
+	require.NoError(t, json.Unmarshal([]byte(obj.Annotations[fleet.RolloutAuditAnnotation]), &entries))
+	assert.Len(t, entries, 1)
+}
+
+func newFakeFleet() *fakeFleet {
+	f := &fakeFleet{
+		clusters: []fleet.Cluster{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "prod", Labels: map[string]string{"env": "prod"}},
+				Status:     fleet.ClusterStatus{Namespace: "cluster-prod"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "dev", Labels: map[string]string{"env": "dev"}},
+				Status:     fleet.ClusterStatus{Namespace: "cluster-dev"},
+			},
+		},
+		clusterGroups: []fleet.ClusterGroup{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "production"},
+			Spec:       fleet.ClusterGroupSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		}},
+		deployments: map[string]*fleet.BundleDeployment{},
+	}
+	for _, bundle := range []struct{ name, repo string }{{"repo-app", "repo"}, {"repo-db", "repo"}, {"other-app", "other"}} {
+		f.bundles = append(f.bundles, fleet.Bundle{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fleet-default",
+			Name:      bundle.name,
+			Labels:    map[string]string{fleet.RepoLabel: bundle.repo},
+		}})
+		for _, ns := range []string{"cluster-prod", "cluster-dev"} {
+			f.deployments[ns+"/"+bundle.name] = &fleet.BundleDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+					Name:      bundle.name,
+					Labels: map[string]string{
+						"fleet.cattle.io/bundle-name":      bundle.name,
+						"fleet.cattle.io/bundle-namespace": "fleet-default",
+					},
+				},
+				Spec: fleet.BundleDeploymentSpec{DeploymentID: "s-1", StagedDeploymentID: "s-1"},
+			}
+		}
+	}
+	return f
+}
+
+func newClient(f *fakeFleet) *client.Client {
+	return &client.Client{Fleet: f, Namespace: "fleet-default"}
+}
+
+func TestPromote(t *testing.T) {
+	f := newFakeFleet()
+	staged := f.deployments["cluster-prod/repo-app"]
+	staged.Spec.StagedDeploymentID = "s-2"
+	staged.Spec.StagedOptions = fleet.BundleDeploymentOptions{TargetNamespace: "app"}
+	f.deployments["cluster-dev/repo-db"].Spec.StagedDeploymentID = "s-2"
+
+	out := &bytes.Buffer{}
+	err := updateBundleDeployments(newClient(f), ActionPromote, &Options{
+		Scope:  Scope{GitRepo: "repo", ClusterGroup: "production"},
+		Output: out,
+		Reason: "tested",
+	}, promote)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bundledeployment cluster-prod/repo-app (cluster prod): promoted\n", out.String())
+	assert.Equal(t, 1, f.updates)
+	promoted := f.deployments["cluster-prod/repo-app"]
+	assert.Equal(t, "s-2", promoted.Spec.DeploymentID)
+	assert.Equal(t, "app", promoted.Spec.Options.TargetNamespace)
+
+	var entries []AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(promoted.Annotations[fleet.RolloutAuditAnnotation]), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, ActionPromote, entries[0].Action)
+	assert.Equal(t, "tested", entries[0].Reason)
+
+	// the staged deployment on dev is outside of the cluster group
+	assert.Equal(t, "s-1", f.deployments["cluster-dev/repo-db"].Spec.DeploymentID)
+
+	out.Reset()
+	err = updateBundleDeployments(newClient(f), ActionPromote, &Options{
+		Scope:  Scope{GitRepo: "repo", ClusterGroup: "production"},
+		Output: out,
+	}, promote)
+	require.NoError(t, err)
+	assert.Equal(t, "no bundle deployments to promote\n", out.String())
+}
+
+func TestRedeploy(t *testing.T) {
+	f := newFakeFleet()
+	generation := int64(3)
+	f.deployments["cluster-dev/repo-app"].Status.SyncGeneration = &generation
+	f.deployments["cluster-prod/repo-app"].Spec.Options.ForceSyncGeneration = 5
+
+	out := &bytes.Buffer{}
+	err := updateBundleDeployments(newClient(f), ActionRedeploy, &Options{
+		Scope:  Scope{Bundle: "repo-app"},
+		Output: out,
+	}, redeploy)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bundledeployment cluster-dev/repo-app (cluster dev): redeployed\n"+
+		"bundledeployment cluster-prod/repo-app (cluster prod): redeployed\n", out.String())
+	assert.Equal(t, int64(4), f.deployments["cluster-dev/repo-app"].Spec.Options.ForceSyncGeneration)
+	assert.Equal(t, int64(6), f.deployments["cluster-prod/repo-app"].Spec.Options.ForceSyncGeneration)
+	assert.Equal(t, int64(0), f.deployments["cluster-prod/repo-db"].Spec.Options.ForceSyncGeneration)
+	assert.Contains(t, f.deployments["cluster-dev/repo-app"].Annotations, fleet.RolloutAuditAnnotation)
+}
+
+func TestRedeployScope(t *testing.T) {
+	assert.Equal(t, ErrNoScope, Redeploy(nil, &Options{}))
+	assert.Equal(t, ErrNoScope, Promote(nil, &Options{}))
+
+	f := newFakeFleet()
+	err := updateBundleDeployments(newClient(f), ActionRedeploy, &Options{Scope: Scope{Bundle: "missing"}, Output: &bytes.Buffer{}}, redeploy)
+	assert.Error(t, err)
+	err = updateBundleDeployments(newClient(f), ActionRedeploy, &Options{Scope: Scope{ClusterSelector: "env=staging"}, Output: &bytes.Buffer{}}, redeploy)
+	assert.Error(t, err)
+
+	// a cluster selector alone selects the deployments of all bundles
+	out := &bytes.Buffer{}
+	err = updateBundleDeployments(newClient(f), ActionRedeploy, &Options{Scope: Scope{ClusterSelector: "env=dev"}, Output: out}, redeploy)
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.updates)
+}
@@ -0,0 +1,321 @@
+// Package status prints the state of a GitRepo, Bundle or Cluster and the
+// resources they relate to as a tree. (fleetstatus)
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/condition"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	KindGitRepo          = "GitRepo"
+	KindBundle           = "Bundle"
+	KindBundleDeployment = "BundleDeployment"
+	KindCluster          = "Cluster"
+
+	FormatTable = "table"
+	FormatJSON  = "json"
+
+	dependencyPrefix = "dependent bundle(s) are not ready: "
+)
+
+// Options configures how Status prints the tree.
+type Options struct {
+	Output   io.Writer
+	Format   string
+	Watch    bool
+	Interval time.Duration
+}
+
+// Node is a resource in the status tree, with the details explaining why it
+// is not ready.
+type Node struct {
+	Kind       string                  `json:"kind"`
+	Namespace  string                  `json:"namespace,omitempty"`
+	Name       string                  `json:"name"`
+	State      string                  `json:"state,omitempty"`
+	Ready      string                  `json:"ready,omitempty"`
+	Message    string                  `json:"message,omitempty"`
+	Summary    *fleet.BundleSummary    `json:"summary,omitempty"`
+	Partitions []fleet.PartitionStatus `json:"partitions,omitempty"`
+	NonReady   []fleet.NonReadyStatus  `json:"nonReady,omitempty"`
+	Modified   []fleet.ModifiedStatus  `json:"modified,omitempty"`
+	BlockedBy  []string                `json:"blockedBy,omitempty"`
+	Children   []*Node                 `json:"children,omitempty"`
+}
+
+// Status prints the tree for the resource of kind and name in the namespace
+// of the client. In watch mode the tree is printed again whenever it changes,
+// until the context is done.
+func Status(ctx context.Context, client *client.Getter, kind, name string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+
+	c, err := client.Get()
+	if err != nil {
+		return err
+	}
+
+	var last string
+	for {
+		node, err := Tree(c, kind, name)
+		if err != nil {
+			return err
+		}
+
+		out := &strings.Builder{}
+		if err := Print(out, opts.Format, node); err != nil {
+			return err
+		}
+
+		if out.String() != last {
+			if last != "" && opts.Format != FormatJSON {
+				fmt.Fprintf(opts.Output, "\n# %s\n", time.Now().Format(time.RFC3339))
+			}
+			if _, err := io.WriteString(opts.Output, out.String()); err != nil {
+				return err
+			}
+			last = out.String()
+		}
+
+		if !opts.Watch {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// Tree builds the status tree for the resource of kind and name. GitRepos
+// contain their bundles, bundles their bundle deployments and bundle
+// deployments the cluster they are deployed to. For clusters, the tree
+// contains the bundle deployments of the cluster.
+func Tree(c *client.Client, kind, name string) (*Node, error) {
+	clusters, err := clustersByNamespace(c)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(kind) {
+	case "gitrepo", "gitrepos":
+		return gitRepoTree(c, name, clusters)
+	case "bundle", "bundles":
+		bundle, err := c.Fleet.Bundle().Get(c.Namespace, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return bundleTree(c, bundle, clusters)
+	case "cluster", "clusters":
+		return clusterTree(c, name)
+	}
+	return nil, fmt.Errorf("unknown kind %q, must be one of gitrepo, bundle or cluster", kind)
+}
+
+func gitRepoTree(c *client.Client, name string, clusters map[string]*fleet.Cluster) (*Node, error) {
+	gitrepo, err := c.Fleet.GitRepo().Get(c.Namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := gitrepo.Status.Summary
+	node := &Node{
+		Kind:      KindGitRepo,
+		Namespace: gitrepo.Namespace,
+		Name:      gitrepo.Name,
+		State:     gitrepo.Status.Display.State,
+		Ready:     gitrepo.Status.Display.ReadyBundleDeployments,
+		Message:   gitrepo.Status.Display.Message,
+		Summary:   &summary,
+	}
+	if node.Message == "" {
+		node.Message = condition.Cond("Ready").GetMessage(gitrepo)
+	}
+
+	bundles, err := c.Fleet.Bundle().List(c.Namespace, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{fleet.RepoLabel: gitrepo.Name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range bundles.Items {
+		child, err := bundleTree(c, &bundles.Items[i], clusters)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	sortNodes(node.Children)
+	return node, nil
+}
+
+func bundleTree(c *client.Client, bundle *fleet.Bundle, clusters map[string]*fleet.Cluster) (*Node, error) {
+	summary := bundle.Status.Summary
+	node := &Node{
+		Kind:       KindBundle,
+		Namespace:  bundle.Namespace,
+		Name:       bundle.Name,
+		State:      bundle.Status.Display.State,
+		Ready:      bundle.Status.Display.ReadyClusters,
+		Message:    condition.Cond(fleet.BundleConditionReady).GetMessage(bundle),
+		Summary:    &summary,
+		Partitions: bundle.Status.PartitionStatus,
+	}
+	if node.State == "" {
+		node.State = string(fleet.Ready)
+	}
+
+	bds, err := c.Fleet.BundleDeployment().List("", metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			"fleet.cattle.io/bundle-name":      bundle.Name,
+			"fleet.cattle.io/bundle-namespace": bundle.Namespace,
+		}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range bds.Items {
+		child := bundleDeploymentNode(&bds.Items[i])
+		if cluster, ok := clusters[bds.Items[i].Namespace]; ok {
+			child.Children = append(child.Children, clusterNode(cluster))
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	sortNodes(node.Children)
+	return node, nil
+}
+
+func clusterTree(c *client.Client, name string) (*Node, error) {
+	cluster, err := c.Fleet.Cluster().Get(c.Namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	node := clusterNode(cluster)
+	summary := cluster.Status.Summary
+	node.Summary = &summary
+
+	if cluster.Status.Namespace == "" {
+		return node, nil
+	}
+
+	bds, err := c.Fleet.BundleDeployment().List(cluster.Status.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range bds.Items {
+		node.Children = append(node.Children, bundleDeploymentNode(&bds.Items[i]))
+	}
+
+	sortNodes(node.Children)
+	return node, nil
+}
+
+func bundleDeploymentNode(bd *fleet.BundleDeployment) *Node {
+	node := &Node{
+		Kind:      KindBundleDeployment,
+		Namespace: bd.Namespace,
+		Name:      bd.Name,
+		State:     bd.Status.Display.State,
+		NonReady:  bd.Status.NonReadyStatus,
+		Modified:  bd.Status.ModifiedStatus,
+	}
+	if node.State == "" && bd.Status.Ready && bd.Status.NonModified {
+		node.State = string(fleet.Ready)
+	}
+
+	deployed := condition.Cond(fleet.BundleDeploymentConditionDeployed)
+	if deployed.IsFalse(bd) {
+		node.Message = deployed.GetMessage(bd)
+		node.BlockedBy = blockers(node.Message)
+	} else if ready := condition.Cond(fleet.BundleDeploymentConditionReady); ready.IsFalse(bd) {
+		node.Message = ready.GetMessage(bd)
+	}
+
+	return node
+}
+
+func clusterNode(cluster *fleet.Cluster) *Node {
+	return &Node{
+		Kind:      KindCluster,
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+		State:     cluster.Status.Display.State,
+		Ready:     cluster.Status.Display.ReadyBundles,
+		Message:   condition.Cond("Ready").GetMessage(cluster),
+	}
+}
+
+// blockers returns the bundles a bundle deployment waits for, as reported by
+// the agent in the Deployed condition.
+func blockers(message string) []string {
+	if !strings.HasPrefix(message, dependencyPrefix) {
+		return nil
+	}
+	list := strings.TrimPrefix(message, dependencyPrefix)
+	list = strings.TrimSuffix(strings.TrimPrefix(list, "["), "]")
+	return strings.Fields(list)
+}
+
+// clustersByNamespace maps the cluster namespaces, which contain the bundle
+// deployments, to the clusters in the namespace of the client.
+func clustersByNamespace(c *client.Client) (map[string]*fleet.Cluster, error) {
+	clusters, err := c.Fleet.Cluster().List(c.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]*fleet.Cluster{}
+	for i := range clusters.Items {
+		if ns := clusters.Items[i].Status.Namespace; ns != "" {
+			result[ns] = &clusters.Items[i]
+		}
+	}
+	return result, nil
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Namespace != nodes[j].Namespace {
+			return nodes[i].Namespace < nodes[j].Namespace
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// Print writes the tree as a table or as JSON.
+func Print(w io.Writer, format string, node *Node) error {
+	switch format {
+	case "", FormatTable:
+		return printTable(w, node)
+	case FormatJSON:
+		data, err := json.MarshalIndent(node, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	return fmt.Errorf("unknown output format %q, must be table or json", format)
+}
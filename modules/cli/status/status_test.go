@@ -0,0 +1,60 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	"github.com/rancher/wrangler/pkg/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBundleDeploymentNode(t *testing.T) {
+	bd := &fleet.BundleDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-ns", Name: "app"},
+		Status: fleet.BundleDeploymentStatus{
+			Conditions: []genericcondition.GenericCondition{{
+				Type:    fleet.BundleDeploymentConditionDeployed,
+				Status:  "False",
+				Message: "dependent bundle(s) are not ready: [db cache]",
+			}},
+		},
+	}
+
+	node := bundleDeploymentNode(bd)
+	assert.Equal(t, []string{"db", "cache"}, node.BlockedBy)
+	assert.Equal(t, "dependent bundle(s) are not ready: [db cache]", node.Message)
+}
+
+func TestPrintTable(t *testing.T) {
+	tree := &Node{
+		Kind: KindGitRepo, Namespace: "fleet-local", Name: "repo", State: "NotReady", Ready: "0/1",
+		Children: []*Node{{
+			Kind: KindBundle, Namespace: "fleet-local", Name: "repo-app", State: "Modified", Ready: "0/1",
+			Partitions: []fleet.PartitionStatus{{Name: "All", Count: 1, MaxUnavailable: 1, Unavailable: 1,
+				Summary: fleet.BundleSummary{DesiredReady: 1}}},
+			Children: []*Node{{
+				Kind: KindBundleDeployment, Namespace: "cluster-ns", Name: "repo-app", State: "Modified",
+				NonReady: []fleet.NonReadyStatus{{Kind: "Deployment", Namespace: "app", Name: "web",
+					Summary: summary.Summary{State: "in-progress", Message: []string{"waiting"}}}},
+				Modified: []fleet.ModifiedStatus{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "app", Name: "cfg", Patch: `{"data":null}`}},
+				Children: []*Node{{Kind: KindCluster, Namespace: "fleet-local", Name: "local", State: "Active", Ready: "0/1"}},
+			}},
+		}},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, Print(out, FormatTable, tree))
+	assert.Equal(t, `RESOURCE                                    STATE     READY  MESSAGE
+GitRepo fleet-local/repo                    NotReady  0/1    
+└─ Bundle fleet-local/repo-app              Modified  0/1    
+   │                                                         partition All: 1/1 unavailable (max 1), 0/1 ready
+   └─ BundleDeployment cluster-ns/repo-app  Modified         
+      │                                                      not ready: Deployment app/web in-progress: waiting
+      │                                                      configmap.v1 app/cfg modified {"data":null}
+      └─ Cluster fleet-local/local          Active    0/1    
+`, out.String())
+}
@@ -0,0 +1,78 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printTable prints the tree with one row per node. The details of a node are
+// printed as additional rows below it, in the message column.
+func printTable(w io.Writer, node *Node) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tSTATE\tREADY\tMESSAGE")
+	printNode(tw, node, "", "")
+	return tw.Flush()
+}
+
+func printNode(w io.Writer, node *Node, prefix, childPrefix string) {
+	name := node.Name
+	if node.Namespace != "" {
+		name = node.Namespace + "/" + node.Name
+	}
+	fmt.Fprintf(w, "%s%s %s\t%s\t%s\t%s\n", prefix, node.Kind, name, node.State, node.Ready, oneLine(node.Message))
+
+	detailPrefix := childPrefix + "│  "
+	if len(node.Children) == 0 {
+		detailPrefix = childPrefix + "   "
+	}
+	for _, detail := range details(node) {
+		fmt.Fprintf(w, "%s\t\t\t%s\n", detailPrefix, detail)
+	}
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printNode(w, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			printNode(w, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// details lists the partition progress, dependency blockers, non-ready
+// resources and modified resources of a node.
+func details(node *Node) []string {
+	var result []string
+
+	for _, p := range node.Partitions {
+		result = append(result, fmt.Sprintf("partition %s: %d/%d unavailable (max %d), %d/%d ready",
+			p.Name, p.Unavailable, p.Count, p.MaxUnavailable, p.Summary.Ready, p.Summary.DesiredReady))
+	}
+
+	if len(node.BlockedBy) > 0 {
+		result = append(result, "blocked by: "+strings.Join(node.BlockedBy, ", "))
+	}
+
+	for _, nr := range node.NonReady {
+		name := nr.Name
+		if nr.Namespace != "" {
+			name = nr.Namespace + "/" + nr.Name
+		}
+		msg := fmt.Sprintf("not ready: %s %s %s", nr.Kind, name, nr.Summary.State)
+		if len(nr.Summary.Message) > 0 {
+			msg += ": " + oneLine(strings.Join(nr.Summary.Message, "; "))
+		}
+		result = append(result, msg)
+	}
+
+	for _, m := range node.Modified {
+		result = append(result, oneLine(m.String()))
+	}
+
+	return result
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
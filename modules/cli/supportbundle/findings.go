@@ -0,0 +1,168 @@
+package supportbundle
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/genericcondition"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// agentOfflineAfter is how long an agent may not check in before its
+// cluster is reported.
+const agentOfflineAfter = 15 * time.Minute
+
+// Finding is a suspicious state of a collected resource.
+type Finding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Namespace == "" {
+		return fmt.Sprintf("%s %s: %s", f.Kind, f.Name, f.Message)
+	}
+	return fmt.Sprintf("%s %s/%s: %s", f.Kind, f.Namespace, f.Name, f.Message)
+}
+
+// PrintSummary writes one line per finding.
+func PrintSummary(w io.Writer, findings []Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No suspicious state found")
+		return
+	}
+	fmt.Fprintf(w, "Found %d suspicious states:\n", len(findings))
+	for _, f := range findings {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+}
+
+func gitRepoFindings(gitrepo *fleet.GitRepo) []Finding {
+	finding := newFinding("GitRepo", gitrepo.Namespace, gitrepo.Name)
+
+	result := conditionFindings(finding, gitrepo.Status.Conditions)
+	if gitrepo.Status.Commit == "" {
+		result = append(result, finding("no commit has been fetched from %s", gitrepo.Spec.Repo))
+	}
+	for _, err := range gitrepo.Status.ResourceErrors {
+		result = append(result, finding("%s", err))
+	}
+	return result
+}
+
+func bundleFindings(bundle *fleet.Bundle) []Finding {
+	finding := newFinding("Bundle", bundle.Namespace, bundle.Name)
+
+	result := conditionFindings(finding, bundle.Status.Conditions)
+	if bundle.Status.Summary.DesiredReady == 0 {
+		result = append(result, finding("no cluster matches the targets of the bundle"))
+	}
+	return result
+}
+
+func bundleDeploymentFindings(bd *fleet.BundleDeployment) []Finding {
+	finding := newFinding("BundleDeployment", bd.Namespace, bd.Name)
+
+	result := conditionFindings(finding, bd.Status.Conditions)
+	if bd.Status.AppliedDeploymentID != bd.Spec.DeploymentID {
+		applied := bd.Status.AppliedDeploymentID
+		if applied == "" {
+			applied = "none"
+		}
+		result = append(result, finding("deployment %s is not applied, applied is %s", bd.Spec.DeploymentID, applied))
+	}
+	for _, nonReady := range bd.Status.NonReadyStatus {
+		msg := fmt.Sprintf("%s %s/%s is %s", nonReady.Kind, nonReady.Namespace, nonReady.Name, nonReady.Summary.State)
+		if len(nonReady.Summary.Message) > 0 {
+			msg += ": " + nonReady.Summary.Message[0]
+		}
+		result = append(result, finding("%s", msg))
+	}
+	for _, modified := range bd.Status.ModifiedStatus {
+		result = append(result, finding("%s", modified.String()))
+	}
+	return result
+}
+
+func clusterFindings(cluster *fleet.Cluster, now time.Time) []Finding {
+	finding := newFinding("Cluster", cluster.Namespace, cluster.Name)
+
+	result := conditionFindings(finding, cluster.Status.Conditions)
+	agent := cluster.Status.Agent
+	if agent.LastSeen.IsZero() {
+		result = append(result, finding("agent has never checked in"))
+	} else if since := now.Sub(agent.LastSeen.Time); since > agentOfflineAfter {
+		result = append(result, finding("agent was last seen %s ago", since.Round(time.Second)))
+	}
+	if agent.NonReadyNodes > 0 {
+		result = append(result, finding("%d nodes are not ready: %v", agent.NonReadyNodes, agent.NonReadyNodeNames))
+	}
+	return result
+}
+
+func jobFindings(job *batchv1.Job) []Finding {
+	finding := newFinding("Job", job.Namespace, job.Name)
+
+	var result []Finding
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			result = append(result, finding("job failed: %s", cond.Message))
+		}
+	}
+	return result
+}
+
+func podFindings(pod *corev1.Pod) []Finding {
+	finding := newFinding("Pod", pod.Namespace, pod.Name)
+
+	var result []Finding
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+		result = append(result, finding("pod is %s", pod.Status.Phase))
+	}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+			result = append(result, finding("container %s is waiting: %s", status.Name, waiting.Reason))
+		}
+		if status.RestartCount > 0 {
+			msg := fmt.Sprintf("container %s restarted %d times", status.Name, status.RestartCount)
+			if last := status.LastTerminationState.Terminated; last != nil {
+				msg += fmt.Sprintf(", last exit code %d (%s)", last.ExitCode, last.Reason)
+			}
+			result = append(result, finding("%s", msg))
+		}
+	}
+	return result
+}
+
+// conditionFindings reports conditions which are false with a message, or
+// stalled.
+func conditionFindings(finding func(string, ...interface{}) Finding, conds []genericcondition.GenericCondition) []Finding {
+	var result []Finding
+	for _, cond := range conds {
+		switch {
+		case cond.Type == "Stalled" && cond.Status == corev1.ConditionTrue:
+			result = append(result, finding("stalled: %s", cond.Message))
+		case cond.Status == corev1.ConditionFalse && cond.Message != "":
+			result = append(result, finding("%s: %s", cond.Type, cond.Message))
+		}
+	}
+	return result
+}
+
+func newFinding(kind, namespace, name string) func(string, ...interface{}) Finding {
+	return func(format string, args ...interface{}) Finding {
+		return Finding{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Message:   fmt.Sprintf(format, args...),
+		}
+	}
+}
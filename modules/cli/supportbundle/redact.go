@@ -0,0 +1,56 @@
+package supportbundle
+
+import (
+	"encoding/json"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/content"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// redact strips the secret of its data, only the keys are kept.
+func redact(secret *corev1.Secret) {
+	for k := range secret.Data {
+		secret.Data[k] = nil
+	}
+	for k := range secret.StringData {
+		secret.StringData[k] = ""
+	}
+	delete(secret.Annotations, corev1.LastAppliedConfigAnnotation)
+}
+
+// decodeResources decompresses the content of the resources in place.
+func decodeResources(resources []fleet.BundleResource) error {
+	for i := range resources {
+		if resources[i].Encoding == "" {
+			continue
+		}
+		data, err := content.Decode(resources[i].Content, resources[i].Encoding)
+		if err != nil {
+			return err
+		}
+		resources[i].Content = string(data)
+		resources[i].Encoding = ""
+	}
+	return nil
+}
+
+// decodedContent is a content resource with its manifest decompressed.
+type decodedContent struct {
+	Name      string                 `json:"name"`
+	Resources []fleet.BundleResource `json:"resources,omitempty"`
+}
+
+func decodeContent(c *fleet.Content) (*decodedContent, error) {
+	data, err := content.GUnzip(c.Content)
+	if err != nil {
+		return nil, err
+	}
+	result := &decodedContent{Name: c.Name}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	result.Name = c.Name
+	return result, decodeResources(result.Resources)
+}
@@ -0,0 +1,465 @@
+// Package supportbundle collects the fleet resources, helm releases, gitjob
+// pods and logs related to a GitRepo or Cluster into a redacted archive for
+// troubleshooting. (fleetsupport)
+package supportbundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/modules/cli/pkg/client"
+	"github.com/rancher/fleet/modules/cli/pkg/tarball"
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/kubeconfig"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	KindGitRepo = "GitRepo"
+	KindCluster = "Cluster"
+
+	localAgentNamespace = "cattle-fleet-local-system"
+	summaryFile         = "summary.txt"
+)
+
+type Options struct {
+	Output io.Writer
+	// SystemNamespace contains the fleet controller, gitjob and the agent
+	// of the local cluster
+	SystemNamespace string
+	// Decode decompresses the resources of bundles and contents
+	Decode bool
+	// TailLines limits the log lines of each container, all lines are
+	// collected if zero
+	TailLines int64
+}
+
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+type collector struct {
+	client  *client.Client
+	k8s     kubernetes.Interface
+	w       *tarball.Writer
+	opts    *Options
+	now     time.Time
+	seen    map[string]bool
+	results []Finding
+}
+
+// Collect writes the resources related to the GitRepo or Cluster name, in
+// the namespace of the client, into the archive filename and prints a
+// summary of the suspicious states it found. Bundle deployments are followed
+// to their content and helm release, helm releases are only found if the
+// client is connected to the downstream cluster, e.g. for the local cluster.
+// The pods and logs of the fleet controller, gitjob and the local agent are
+// always collected. Secrets are stripped of their data.
+func Collect(ctx context.Context, getter *client.Getter, kind, name, filename string, opts *Options) ([]Finding, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.Output == nil {
+		opts.Output = io.Discard
+	}
+
+	c, err := getter.Get()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := kubeconfig.GetNonInteractiveClientConfigWithContext(getter.Kubeconfig, getter.Context).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	k8s, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := tarball.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	col := &collector{
+		client: c,
+		k8s:    k8s,
+		w:      w,
+		opts:   opts,
+		now:    time.Now(),
+		seen:   map[string]bool{},
+	}
+	if err := col.collect(ctx, kind, name); err != nil {
+		w.Close()
+		os.Remove(filename)
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return col.results, nil
+}
+
+func (c *collector) collect(ctx context.Context, kind, name string) error {
+	switch strings.ToLower(kind) {
+	case "gitrepo", "gitrepos":
+		if err := c.gitRepo(ctx, name); err != nil {
+			return err
+		}
+	case "cluster", "clusters":
+		if err := c.cluster(ctx, name); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown kind %q, must be one of gitrepo or cluster", kind)
+	}
+
+	for _, ns := range []string{c.opts.SystemNamespace, localAgentNamespace} {
+		if ns == "" {
+			continue
+		}
+		if err := c.pods(ctx, ns, labels.Everything()); err != nil {
+			return err
+		}
+	}
+
+	summary := &strings.Builder{}
+	PrintSummary(summary, c.results)
+	if err := c.w.Add(summaryFile, []byte(summary.String())); err != nil {
+		return err
+	}
+	_, err := io.WriteString(c.opts.Output, summary.String())
+	return err
+}
+
+func (c *collector) gitRepo(ctx context.Context, name string) error {
+	gitrepo, err := c.client.Fleet.GitRepo().Get(c.client.Namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := c.add(gitrepo, fleet.SchemeGroupVersion.WithKind("GitRepo")); err != nil {
+		return err
+	}
+	c.results = append(c.results, gitRepoFindings(gitrepo)...)
+
+	bundles, err := c.client.Fleet.Bundle().List(gitrepo.Namespace, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{fleet.RepoLabel: gitrepo.Name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	clusterNamespaces := map[string]bool{}
+	for i := range bundles.Items {
+		bds, err := c.bundle(&bundles.Items[i])
+		if err != nil {
+			return err
+		}
+		for _, bd := range bds {
+			clusterNamespaces[bd.Namespace] = true
+		}
+	}
+
+	clusters, err := c.client.Fleet.Cluster().List(gitrepo.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if !clusterNamespaces[cluster.Status.Namespace] {
+			continue
+		}
+		if err := c.add(cluster, fleet.SchemeGroupVersion.WithKind("Cluster")); err != nil {
+			return err
+		}
+		c.results = append(c.results, clusterFindings(cluster, c.now)...)
+	}
+
+	return c.gitJobs(ctx, gitrepo)
+}
+
+func (c *collector) cluster(ctx context.Context, name string) error {
+	cluster, err := c.client.Fleet.Cluster().Get(c.client.Namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := c.add(cluster, fleet.SchemeGroupVersion.WithKind("Cluster")); err != nil {
+		return err
+	}
+	c.results = append(c.results, clusterFindings(cluster, c.now)...)
+
+	if cluster.Status.Namespace == "" {
+		return nil
+	}
+	bds, err := c.client.Fleet.BundleDeployment().List(cluster.Status.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range bds.Items {
+		bd := &bds.Items[i]
+		if err := c.bundleDeployment(bd); err != nil {
+			return err
+		}
+
+		ns, name := bd.Labels["fleet.cattle.io/bundle-namespace"], bd.Labels["fleet.cattle.io/bundle-name"]
+		if ns == "" || name == "" || c.seen[file("bundles", ns, name)] {
+			continue
+		}
+		bundle, err := c.client.Fleet.Bundle().Get(ns, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			c.results = append(c.results, Finding{Kind: "BundleDeployment", Namespace: bd.Namespace, Name: bd.Name,
+				Message: fmt.Sprintf("bundle %s/%s not found", ns, name)})
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := c.addBundle(bundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundle adds the bundle and its bundle deployments and returns the bundle
+// deployments.
+func (c *collector) bundle(bundle *fleet.Bundle) ([]fleet.BundleDeployment, error) {
+	if err := c.addBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	bds, err := c.client.Fleet.BundleDeployment().List("", metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			"fleet.cattle.io/bundle-name":      bundle.Name,
+			"fleet.cattle.io/bundle-namespace": bundle.Namespace,
+		}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range bds.Items {
+		if err := c.bundleDeployment(&bds.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bds.Items, nil
+}
+
+func (c *collector) addBundle(bundle *fleet.Bundle) error {
+	c.results = append(c.results, bundleFindings(bundle)...)
+	if c.opts.Decode {
+		if err := decodeResources(bundle.Spec.Resources); err != nil {
+			return fmt.Errorf("decoding resources of bundle %s/%s: %w", bundle.Namespace, bundle.Name, err)
+		}
+	}
+	return c.add(bundle, fleet.SchemeGroupVersion.WithKind("Bundle"))
+}
+
+func (c *collector) bundleDeployment(bd *fleet.BundleDeployment) error {
+	if err := c.add(bd, fleet.SchemeGroupVersion.WithKind("BundleDeployment")); err != nil {
+		return err
+	}
+	c.results = append(c.results, bundleDeploymentFindings(bd)...)
+
+	if id, _, _ := strings.Cut(bd.Spec.DeploymentID, ":"); id != "" {
+		found, err := c.content(id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			c.results = append(c.results, Finding{Kind: "BundleDeployment", Namespace: bd.Namespace, Name: bd.Name,
+				Message: fmt.Sprintf("content %s of the deployment is missing", id)})
+		}
+	}
+
+	if bd.Status.Release != "" {
+		return c.helmRelease(bd)
+	}
+	return nil
+}
+
+func (c *collector) content(id string) (bool, error) {
+	if c.seen[file("contents", "", id)] {
+		return true, nil
+	}
+	content, err := c.client.Fleet.Content().Get(id, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !c.opts.Decode {
+		return true, c.add(content, fleet.SchemeGroupVersion.WithKind("Content"))
+	}
+
+	m, err := decodeContent(content)
+	if err != nil {
+		return true, fmt.Errorf("decoding content %s: %w", id, err)
+	}
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return true, err
+	}
+	c.seen[file("contents", "", id)] = true
+	return true, c.w.Add(file("contents", "", id), data)
+}
+
+// helmRelease adds the release secrets of the bundle deployment's release,
+// which are found if the client is connected to the cluster the bundle
+// deployment is deployed to.
+func (c *collector) helmRelease(bd *fleet.BundleDeployment) error {
+	ns, name, version, ok := parseRelease(bd.Status.Release)
+	if !ok {
+		return nil
+	}
+
+	secrets, err := c.client.Core.Secret().List(ns, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"owner": "helm", "name": name}).String(),
+	})
+	if err != nil {
+		logrus.Warnf("listing helm release secrets of %s/%s: %v", ns, name, err)
+		return nil
+	}
+	if len(secrets.Items) == 0 {
+		logrus.Debugf("no helm release secrets of %s/%s found, it is not deployed to this cluster", ns, name)
+		return nil
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Labels["version"] == version {
+			if status := secret.Labels["status"]; status != "deployed" {
+				c.results = append(c.results, Finding{Kind: "BundleDeployment", Namespace: bd.Namespace, Name: bd.Name,
+					Message: fmt.Sprintf("helm release %s is %s", bd.Status.Release, status)})
+			}
+		}
+		redact(secret)
+		if err := c.add(secret, corev1.SchemeGroupVersion.WithKind("Secret")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitJobs adds the jobs gitjob created for the GitRepo and their pods.
+func (c *collector) gitJobs(ctx context.Context, gitrepo *fleet.GitRepo) error {
+	jobs, err := c.k8s.BatchV1().Jobs(gitrepo.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logrus.Warnf("listing jobs in %s: %v", gitrepo.Namespace, err)
+		return nil
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		owner := metav1.GetControllerOf(job)
+		if owner == nil || owner.Kind != "GitJob" || owner.Name != gitrepo.Name {
+			continue
+		}
+		if err := c.add(job, batchv1.SchemeGroupVersion.WithKind("Job")); err != nil {
+			return err
+		}
+		c.results = append(c.results, jobFindings(job)...)
+		if err := c.pods(ctx, job.Namespace, labels.SelectorFromSet(labels.Set{"job-name": job.Name})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pods adds the pods in namespace and the logs of their containers. If a
+// container restarted, the logs of the previous container are added too.
+func (c *collector) pods(ctx context.Context, namespace string, selector labels.Selector) error {
+	pods, err := c.k8s.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		logrus.Warnf("listing pods in %s: %v", namespace, err)
+		return nil
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if err := c.add(pod, corev1.SchemeGroupVersion.WithKind("Pod")); err != nil {
+			return err
+		}
+		c.results = append(c.results, podFindings(pod)...)
+
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if err := c.logs(ctx, pod, status.Name, false); err != nil {
+				return err
+			}
+			if status.RestartCount > 0 {
+				if err := c.logs(ctx, pod, status.Name, true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *collector) logs(ctx context.Context, pod *corev1.Pod, container string, previous bool) error {
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if c.opts.TailLines > 0 {
+		opts.TailLines = &c.opts.TailLines
+	}
+	data, err := c.k8s.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+	if err != nil {
+		logrus.Warnf("getting logs of %s/%s container %s: %v", pod.Namespace, pod.Name, container, err)
+		return nil
+	}
+
+	name := container + ".log"
+	if previous {
+		name = container + ".previous.log"
+	}
+	return c.w.Add(path.Join("logs", pod.Namespace, pod.Name, name), data)
+}
+
+// add writes obj as YAML, without managed fields, into a directory named
+// after its kind. Objects are only added once.
+func (c *collector) add(obj object, gvk schema.GroupVersionKind) error {
+	name := file(strings.ToLower(gvk.Kind)+"s", obj.GetNamespace(), obj.GetName())
+	if c.seen[name] {
+		return nil
+	}
+	c.seen[name] = true
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return c.w.Add(name, data)
+}
+
+func file(dir, namespace, name string) string {
+	return path.Join(dir, namespace, name+".yaml")
+}
+
+// parseRelease splits the release of a bundle deployment status, which is
+// formatted as namespace/name:version.
+func parseRelease(release string) (string, string, string, bool) {
+	ns, rest, ok := strings.Cut(release, "/")
+	if !ok {
+		return "", "", "", false
+	}
+	name, version, ok := strings.Cut(rest, ":")
+	return ns, name, version, ok
+}
@@ -0,0 +1,116 @@
+package supportbundle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/content"
+	"github.com/rancher/fleet/pkg/manifest"
+
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRedact(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{corev1.LastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`},
+		},
+		Data:       map[string][]byte{"password": []byte("secret")},
+		StringData: map[string]string{"token": "secret"},
+	}
+	redact(secret)
+	assert.Equal(t, map[string][]byte{"password": nil}, secret.Data)
+	assert.Equal(t, map[string]string{"token": ""}, secret.StringData)
+	assert.Empty(t, secret.Annotations)
+}
+
+func TestDecodeContent(t *testing.T) {
+	encoded, err := content.Base64GZ([]byte("kind: ConfigMap"))
+	require.NoError(t, err)
+	m, err := manifest.New([]fleet.BundleResource{
+		{Name: "cm.yaml", Content: encoded, Encoding: "base64+gz"},
+		{Name: "plain.yaml", Content: "kind: Secret"},
+	})
+	require.NoError(t, err)
+	data, _, err := m.Content()
+	require.NoError(t, err)
+	gz, err := content.Gzip(data)
+	require.NoError(t, err)
+
+	decoded, err := decodeContent(&fleet.Content{ObjectMeta: metav1.ObjectMeta{Name: "s-123"}, Content: gz})
+	require.NoError(t, err)
+	assert.Equal(t, "s-123", decoded.Name)
+	assert.Equal(t, []fleet.BundleResource{
+		{Name: "cm.yaml", Content: "kind: ConfigMap"},
+		{Name: "plain.yaml", Content: "kind: Secret"},
+	}, decoded.Resources)
+}
+
+func TestFindings(t *testing.T) {
+	now := time.Now()
+
+	bd := &fleet.BundleDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-ns", Name: "app"},
+		Spec:       fleet.BundleDeploymentSpec{DeploymentID: "s-2:abc"},
+		Status: fleet.BundleDeploymentStatus{
+			AppliedDeploymentID: "s-1:abc",
+			Conditions: []genericcondition.GenericCondition{
+				{Type: "Ready", Status: corev1.ConditionFalse, Message: "not ready"},
+				{Type: "Deployed", Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	cluster := &fleet.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "down"},
+		Status: fleet.ClusterStatus{
+			Agent: fleet.AgentStatus{LastSeen: metav1.NewTime(now.Add(-time.Hour))},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-fleet-system", Name: "fleet-controller-1"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "fleet-controller",
+				RestartCount:         3,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}},
+		},
+	}
+
+	var findings []Finding
+	findings = append(findings, bundleDeploymentFindings(bd)...)
+	findings = append(findings, clusterFindings(cluster, now)...)
+	findings = append(findings, podFindings(pod)...)
+
+	out := &strings.Builder{}
+	PrintSummary(out, findings)
+	assert.Equal(t, `Found 5 suspicious states:
+  BundleDeployment cluster-ns/app: Ready: not ready
+  BundleDeployment cluster-ns/app: deployment s-2:abc is not applied, applied is s-1:abc
+  Cluster fleet-default/down: agent was last seen 1h0m0s ago
+  Pod cattle-fleet-system/fleet-controller-1: container fleet-controller is waiting: CrashLoopBackOff
+  Pod cattle-fleet-system/fleet-controller-1: container fleet-controller restarted 3 times, last exit code 1 (Error)
+`, out.String())
+
+	out.Reset()
+	PrintSummary(out, nil)
+	assert.Equal(t, "No suspicious state found\n", out.String())
+}
+
+func TestParseRelease(t *testing.T) {
+	ns, name, version, ok := parseRelease("default/app:3")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"default", "app", "3"}, []string{ns, name, version})
+
+	_, _, _, ok = parseRelease("app")
+	assert.False(t, ok)
+}
@@ -71,7 +71,7 @@ func AgentWithConfig(ctx context.Context, agentNamespace, controllerNamespace, a
 		return err
 	}
 
-	mo := opts.ManifestOptions
+	mo := opts.ManifestOptions.WithSchedulingDefaults(cfg)
 	mo.AgentImage = cfg.AgentImage
 	mo.SystemDefaultRegistry = cfg.SystemDefaultRegistry
 	mo.AgentImagePullPolicy = cfg.AgentImagePullPolicy
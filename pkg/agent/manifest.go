@@ -27,13 +27,41 @@ const (
 )
 
 type ManifestOptions struct {
-	AgentEnvVars          []corev1.EnvVar
-	AgentImage            string
-	AgentImagePullPolicy  string
-	CheckinInterval       string
-	Generation            string
-	PrivateRepoURL        string
-	SystemDefaultRegistry string
+	AgentEnvVars           []corev1.EnvVar
+	AgentImage             string
+	AgentImagePullPolicy   string
+	AgentTolerations       []corev1.Toleration
+	AgentAffinity          *corev1.Affinity
+	AgentResources         *corev1.ResourceRequirements
+	AgentPriorityClassName string
+	AgentReplicas          *int32
+	CheckinInterval        string
+	Generation             string
+	PrivateRepoURL         string
+	SystemDefaultRegistry  string
+}
+
+// WithSchedulingDefaults returns a copy of the options, in which the
+// scheduling settings not set for the cluster are taken from the global
+// config. Tolerations from both are combined.
+func (o ManifestOptions) WithSchedulingDefaults(cfg *config.Config) ManifestOptions {
+	if cfg == nil {
+		return o
+	}
+	o.AgentTolerations = append(append([]corev1.Toleration{}, cfg.AgentTolerations...), o.AgentTolerations...)
+	if o.AgentAffinity == nil {
+		o.AgentAffinity = cfg.AgentAffinity
+	}
+	if o.AgentResources == nil {
+		o.AgentResources = cfg.AgentResources
+	}
+	if o.AgentPriorityClassName == "" {
+		o.AgentPriorityClassName = cfg.AgentPriorityClassName
+	}
+	if o.AgentReplicas == nil {
+		o.AgentReplicas = cfg.AgentReplicas
+	}
+	return o
 }
 
 // Manifest builds and returns a deployment manifest for the fleet-agent with a
@@ -133,6 +161,15 @@ func Manifest(namespace string, agentScope string, opts ManifestOptions) []runti
 			},
 		},
 	}
+	if opts.AgentAffinity != nil {
+		dep.Spec.Template.Spec.Affinity = opts.AgentAffinity
+	}
+	dep.Spec.Template.Spec.Tolerations = append(dep.Spec.Template.Spec.Tolerations, opts.AgentTolerations...)
+	if opts.AgentResources != nil {
+		dep.Spec.Template.Spec.Containers[0].Resources = *opts.AgentResources
+	}
+	dep.Spec.Template.Spec.PriorityClassName = opts.AgentPriorityClassName
+	dep.Spec.Replicas = opts.AgentReplicas
 
 	networkPolicy := &networkv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
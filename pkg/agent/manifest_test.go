@@ -1,6 +1,14 @@
 package agent
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/rancher/fleet/pkg/config"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 func TestImageResolve(t *testing.T) {
 	tests := []struct {
@@ -21,3 +29,49 @@ func TestImageResolve(t *testing.T) {
 		}
 	}
 }
+
+func TestManifestScheduling(t *testing.T) {
+	replicas := int32(2)
+	global := &config.Config{
+		AgentTolerations: []corev1.Toleration{{Key: "global", Operator: corev1.TolerationOpExists}},
+		AgentAffinity:    &corev1.Affinity{},
+	}
+	opts := ManifestOptions{
+		AgentTolerations:       []corev1.Toleration{{Key: "cluster", Operator: corev1.TolerationOpExists}},
+		AgentPriorityClassName: "infra",
+		AgentReplicas:          &replicas,
+		AgentResources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		},
+	}.WithSchedulingDefaults(global)
+
+	var dep *appsv1.Deployment
+	for _, obj := range Manifest("cattle-fleet-system", "", opts) {
+		if d, ok := obj.(*appsv1.Deployment); ok {
+			dep = d
+		}
+	}
+	if dep == nil {
+		t.Fatal("expected agent deployment in manifest")
+	}
+
+	spec := dep.Spec.Template.Spec
+	if n := len(spec.Tolerations); n != 4 {
+		t.Errorf("expected 4 tolerations, got %d", n)
+	}
+	if spec.Tolerations[2].Key != "global" || spec.Tolerations[3].Key != "cluster" {
+		t.Errorf("expected global and cluster tolerations after defaults, got %v", spec.Tolerations)
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity != nil {
+		t.Errorf("expected affinity to be replaced by global affinity, got %v", spec.Affinity)
+	}
+	if spec.PriorityClassName != "infra" {
+		t.Errorf("expected priority class infra, got %s", spec.PriorityClassName)
+	}
+	if dep.Spec.Replicas == nil || *dep.Spec.Replicas != 2 {
+		t.Errorf("expected 2 replicas, got %v", dep.Spec.Replicas)
+	}
+	if mem := spec.Containers[0].Resources.Limits.Memory().String(); mem != "512Mi" {
+		t.Errorf("expected memory limit 512Mi, got %s", mem)
+	}
+}
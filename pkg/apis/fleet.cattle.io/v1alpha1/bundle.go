@@ -151,6 +151,18 @@ type BundleStatus struct {
 	Display                  BundleDisplay     `json:"display,omitempty"`
 	ResourceKey              []ResourceKey     `json:"resourceKey,omitempty"`
 	ObservedGeneration       int64             `json:"observedGeneration"`
+	// ImageOverrides are the latest tags of ImageScans in override mode,
+	// which were set in the options of the bundle
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+}
+
+// ImageOverride is a tag of an ImageScan, which was set in the options of a
+// bundle.
+type ImageOverride struct {
+	ImageScan string `json:"imageScan,omitempty"`
+	Image     string `json:"image,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Digest    string `json:"digest,omitempty"`
 }
 
 type ResourceKey struct {
@@ -220,6 +232,17 @@ type YAMLOptions struct {
 
 type KustomizeOptions struct {
 	Dir string `json:"dir,omitempty"`
+	// Images overrides the name, tag or digest of images in the
+	// kustomization, like the images field of kustomization.yaml.
+	Images []KustomizeImage `json:"images,omitempty"`
+}
+
+// KustomizeImage replaces the image with Name in the kustomize resources.
+type KustomizeImage struct {
+	Name    string `json:"name"`
+	NewName string `json:"newName,omitempty"`
+	NewTag  string `json:"newTag,omitempty"`
+	Digest  string `json:"digest,omitempty"`
 }
 
 type HelmOptions struct {
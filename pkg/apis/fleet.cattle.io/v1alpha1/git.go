@@ -99,6 +99,9 @@ type GitRepoStatus struct {
 	ResourceCounts          GitRepoResourceCounts               `json:"resourceCounts,omitempty"`
 	ResourceErrors          []string                            `json:"resourceErrors,omitempty"`
 	LastSyncedImageScanTime metav1.Time                         `json:"lastSyncedImageScanTime,omitempty"`
+	// ImageUpdatePullRequest is the URL of the open pull request with
+	// image updates, if imageScanCommit.pullRequest is set
+	ImageUpdatePullRequest string `json:"imageUpdatePullRequest,omitempty"`
 }
 
 type GitRepoResourceCounts struct {
@@ -17,6 +17,15 @@ type ImageScan struct {
 	Status ImageScanStatus `json:"status,omitempty"`
 }
 
+const (
+	// ImageScanModeCommit commits the latest tag to the git repository,
+	// into the fields marked with the tag name of the scan
+	ImageScanModeCommit = "commit"
+	// ImageScanModeOverride sets the latest tag in the options of the
+	// bundles of the GitRepo, nothing is committed
+	ImageScanModeOverride = "override"
+)
+
 // API is taken from https://github.com/fluxcd/image-reflector-controller
 type ImageScanSpec struct {
 	// TagName is the tag ref that needs to be put in manifest to replace fields
@@ -25,6 +34,30 @@ type ImageScanSpec struct {
 	// GitRepo reference name
 	GitRepoName string `json:"gitrepoName,omitempty"`
 
+	// GitRepos are the GitRepos updated by the scan in commit mode, e.g.
+	// the GitRepos of the branches of several environments. Used instead
+	// of GitRepoName.
+	// +optional
+	GitRepos []ImageScanGitRepo `json:"gitrepos,omitempty"`
+
+	// Mode is "commit", the default, to commit updates to the git
+	// repository, or "override" to set the latest tag in the options of
+	// the bundles of the GitRepo, without write access to git.
+	// +kubebuilder:validation:Enum=commit;override
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// HelmValue is the path of the helm value which is set to the latest
+	// tag in override mode, e.g. "image.tag".
+	// +optional
+	HelmValue string `json:"helmValue,omitempty"`
+
+	// KustomizeImage is the name of the image in the kustomize resources,
+	// whose tag is set to the latest tag in override mode. Defaults to
+	// image, if helmValue is empty.
+	// +optional
+	KustomizeImage string `json:"kustomizeImage,omitempty"`
+
 	// Image is the name of the image repository
 	// +required
 	Image string `json:"image,omitempty"`
@@ -34,6 +67,13 @@ type ImageScanSpec struct {
 	// +required
 	Interval metav1.Duration `json:"interval,omitempty"`
 
+	// DigestCheckInterval re-checks the digest of the latest tag at this
+	// interval between scans. If a mutable tag, like "stable", is pushed
+	// again, the GitRepo is updated without waiting for its image sync
+	// interval. Requires a setter which writes the digest.
+	// +optional
+	DigestCheckInterval *metav1.Duration `json:"digestCheckInterval,omitempty"`
+
 	// SecretRef can be given the name of a secret containing
 	// credentials to use for the image registry. The secret should be
 	// created with `kubectl create secret docker-registry`, or the
@@ -49,6 +89,89 @@ type ImageScanSpec struct {
 	// selecting the most recent image
 	// +required
 	Policy ImagePolicyChoice `json:"policy"`
+
+	// Pin is a tag, which is used instead of the latest tag selected by
+	// the policy, e.g. to roll back to a previous tag of the history. The
+	// digest is taken from the history, if the tag is in it.
+	// +optional
+	Pin string `json:"pin,omitempty"`
+
+	// HistoryLimit is the number of entries in the history of the status.
+	// Defaults to 10.
+	// +optional
+	HistoryLimit int `json:"historyLimit,omitempty"`
+
+	// Fields are YAML fields, which are set to the latest image without a
+	// setter marker, e.g. "helm.values.image.tag" of a fleet.yaml.
+	// +optional
+	Fields []ImageScanField `json:"fields,omitempty"`
+
+	// FilterTags filters the tags of the image repository before the
+	// policy selects the latest tag, and extracts the value the policy
+	// orders the tags by.
+	// +optional
+	FilterTags *TagFilter `json:"filterTags,omitempty"`
+}
+
+// ImageScanGitRepo references a GitRepo updated by an ImageScan.
+type ImageScanGitRepo struct {
+	// Name of the GitRepo
+	// +required
+	Name string `json:"name"`
+
+	// Paths restricts the update to these paths of the git repository.
+	// Defaults to the paths of the GitRepo.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// After is the name of another GitRepo of the scan. The latest tag is
+	// only committed to this GitRepo once the other one's branch contains
+	// it, e.g. after its pull request was merged, and the GitRepo has
+	// deployed it and is ready.
+	// +optional
+	After string `json:"after,omitempty"`
+
+	// Delay is the time the GitRepo named by After has to be ready with
+	// the latest tag, before this GitRepo is updated.
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+}
+
+// ImageScanField is a field of a YAML file, which is updated by an ImageScan.
+type ImageScanField struct {
+	// File is the YAML file of the field, relative to the root of the git
+	// repository. In the imageScans of a fleet.yaml, it's relative to the
+	// fleet.yaml and defaults to the fleet.yaml itself.
+	// +optional
+	File string `json:"file,omitempty"`
+
+	// Path is the dot separated path of the field, e.g.
+	// "helm.values.image.tag". In files with multiple documents, the field
+	// is updated in every document which has it.
+	// +required
+	Path string `json:"path"`
+
+	// Value is the part of the latest image written to the field: "tag",
+	// "name", "digest", "pinned" or "digest-only", like the suffixes of
+	// setter markers. Defaults to the image with tag.
+	// +kubebuilder:validation:Enum=tag;name;digest;pinned;digest-only
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// TagFilter enables filtering for only a subset of tags based on a set of
+// rules. If no rules are provided, all the tags from the repository will be
+// ordered and compared.
+type TagFilter struct {
+	// Pattern specifies a regular expression pattern used to filter for image
+	// tags, e.g. "^main-[a-f0-9]+-(?P<ts>[0-9]+)$".
+	// +optional
+	Pattern string `json:"pattern"`
+	// Extract allows a capture group to be extracted from the specified
+	// regular expression pattern, useful before tag evaluation, e.g. "$ts".
+	// Defaults to the whole tag.
+	// +optional
+	Extract string `json:"extract"`
 }
 
 // CommitSpec specifies how to commit changes to the git repository
@@ -61,8 +184,67 @@ type CommitSpec struct {
 	AuthorEmail string `json:"authorEmail"`
 	// MessageTemplate provides a template for the commit message,
 	// into which will be interpolated the details of the change made.
+	// It's a Go template with the GitRepo in .GitRepo and the result of
+	// the update in .Updated, e.g.
+	// "{{range .Updated.Changes}}{{.OldTag}} -> {{.NewTag}}{{end}}".
 	// +optional
 	MessageTemplate string `json:"messageTemplate,omitempty"`
+	// SigningKey signs the commits with a GPG or SSH key.
+	// +optional
+	SigningKey *SigningKey `json:"signingKey,omitempty"`
+	// PushBranch is the branch updates are pushed to, instead of the
+	// branch of the GitRepo. The branch is reset to the GitRepo's branch
+	// plus the update commit on every push.
+	// +optional
+	PushBranch string `json:"pushBranch,omitempty"`
+	// PullRequest opens or updates a pull request from PushBranch to the
+	// branch of the GitRepo.
+	// +optional
+	PullRequest *PullRequestSpec `json:"pullRequest,omitempty"`
+}
+
+// SigningKey references the key to sign commits with.
+type SigningKey struct {
+	// SecretRef is a secret with an armored GPG private key in the
+	// "git.asc" key or an SSH private key in the "ssh-privatekey" key,
+	// and an optional "passphrase".
+	// +required
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+const (
+	// PullRequestProviderGitHub uses the API of GitHub or GitHub Enterprise.
+	PullRequestProviderGitHub = "github"
+	// PullRequestProviderGitLab opens merge requests with the GitLab API.
+	PullRequestProviderGitLab = "gitlab"
+	// PullRequestProviderGitea uses the API of Gitea or Forgejo.
+	PullRequestProviderGitea = "gitea"
+	// PullRequestProviderBitbucket uses the Bitbucket Cloud API.
+	PullRequestProviderBitbucket = "bitbucket"
+)
+
+// PullRequestSpec specifies how to open pull requests for image updates
+type PullRequestSpec struct {
+	// Provider is one of "github", "gitlab", "gitea" or "bitbucket".
+	// +required
+	Provider string `json:"provider"`
+	// URL of the provider's API, e.g. "https://gitea.example.com/api/v1".
+	// Defaults to the API of the host of the GitRepo's repo.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Repository is the path of the repository, e.g. "owner/repo" or
+	// "group/subgroup/project" for GitLab. Defaults to the path of the
+	// GitRepo's repo.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// SecretRef is the name of a secret with an API token in the "token"
+	// key and an optional "username" key for basic auth. Defaults to the
+	// GitRepo's basic auth client secret.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// Title of the pull request.
+	// +optional
+	Title string `json:"title,omitempty"`
 }
 
 // ImagePolicyChoice is a union of all the types of policy that can be
@@ -75,6 +257,9 @@ type ImagePolicyChoice struct {
 	// Alphabetical set of rules to use for alphabetical ordering of the tags.
 	// +optional
 	Alphabetical *AlphabeticalPolicy `json:"alphabetical,omitempty"`
+	// Numerical set of rules to use for numerical ordering of the tags.
+	// +optional
+	Numerical *NumericalPolicy `json:"numerical,omitempty"`
 }
 
 // SemVerPolicy specifices a semantic version policy.
@@ -96,6 +281,17 @@ type AlphabeticalPolicy struct {
 	Order string `json:"order,omitempty"`
 }
 
+// NumericalPolicy specifices a numerical ordering policy.
+type NumericalPolicy struct {
+	// Order specifies the sorting order of the tags. Given the integer values
+	// from 0 to 9 as tags, ascending order would select 9, and descending order
+	// would select 0.
+	// +kubebuilder:default:="asc"
+	// +kubebuilder:validation:Enum=asc;desc
+	// +optional
+	Order string `json:"order,omitempty"`
+}
+
 type ImageScanStatus struct {
 	// +optional
 	Conditions []genericcondition.GenericCondition `json:"conditions,omitempty"`
@@ -114,12 +310,58 @@ type ImageScanStatus struct {
 	// LatestDigest is the digest of latest tag
 	LatestDigest string `json:"latestDigest,omitempty"`
 
+	// LastDigestCheckTime is the last time the digest of the latest tag
+	// was checked
+	// +optional
+	LastDigestCheckTime metav1.Time `json:"lastDigestCheckTime,omitempty"`
+
+	// LatestDigestTime is the time the digest of the latest image changed
+	// +optional
+	LatestDigestTime metav1.Time `json:"latestDigestTime,omitempty"`
+
+	// Candidates is the number of tags which passed filterTags and the
+	// policy, from which the latest tag was selected
+	// +optional
+	Candidates int `json:"candidates,omitempty"`
+
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// History lists the latest tags, most recent first
+	// +optional
+	History []ImageScanHistory `json:"history,omitempty"`
+
+	// GitRepos lists the tag last committed to each GitRepo of the scan
+	// +optional
+	GitRepos []ImageScanGitRepoStatus `json:"gitrepos,omitempty"`
+
 	// CannonicalName is the name of the image repository with all the
 	// implied bits made explicit; e.g., `docker.io/library/alpine`
 	// rather than `alpine`.
 	// +optional
 	CanonicalImageName string `json:"canonicalImageName,omitempty"`
 }
+
+// ImageScanHistory is a tag, which was the latest tag of an ImageScan.
+type ImageScanHistory struct {
+	Tag    string `json:"tag,omitempty"`
+	Digest string `json:"digest,omitempty"`
+	// FirstSeen is the time the tag or its digest became the latest
+	FirstSeen metav1.Time `json:"firstSeen,omitempty"`
+	// Commit is the git commit which updated the GitRepo to the tag
+	Commit string `json:"commit,omitempty"`
+	// Pinned is true if the tag was set by spec.pin
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// ImageScanGitRepoStatus is the tag an ImageScan committed to a GitRepo.
+type ImageScanGitRepoStatus struct {
+	Name string `json:"name,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+	// Commit is the head of the GitRepo's branch, which contains the tag.
+	// Updates pushed to another branch are only recorded once the pull
+	// request is merged.
+	Commit string `json:"commit,omitempty"`
+	// UpdateTime is the time the tag was committed
+	UpdateTime metav1.Time `json:"updateTime,omitempty"`
+}
@@ -0,0 +1,101 @@
+package v1alpha1
+
+import (
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NotificationProviderSlack posts to a Slack incoming webhook.
+	NotificationProviderSlack = "slack"
+	// NotificationProviderMSTeams posts to a Microsoft Teams incoming webhook.
+	NotificationProviderMSTeams = "msteams"
+	// NotificationProviderWebhook posts the event, or the rendered
+	// template, to a generic HTTP endpoint.
+	NotificationProviderWebhook = "webhook"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Notification sends a message to an external endpoint, when GitRepos or
+// Bundles in the same namespace change their state.
+type Notification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationSpec   `json:"spec,omitempty"`
+	Status NotificationStatus `json:"status,omitempty"`
+}
+
+type NotificationSpec struct {
+	// Selector matches the labels of GitRepos and Bundles in the
+	// namespace of the notification. An empty selector matches all.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Kinds restricts the notification to "GitRepo" or "Bundle"
+	// resources. Defaults to both.
+	// +optional
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Events restricts the notification to these event reasons. Reasons
+	// are the new state, e.g. "Ready", "ErrApplied" or "GitUpdating", and
+	// "PartitionUnavailable" for a bundle rollout that stalled. Defaults
+	// to all events.
+	// +optional
+	Events []string `json:"events,omitempty"`
+
+	// Provider is one of "slack", "msteams" or "webhook".
+	// +required
+	Provider string `json:"provider"`
+
+	// SecretRef is the name of a secret with the URL of the endpoint in
+	// the "address" key. For webhooks an optional "token" key is sent as
+	// bearer token.
+	// +required
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// Template is a Go template for the message text of Slack and Teams,
+	// or the request body of webhooks. It is rendered with the event,
+	// which has the fields Kind, Namespace, Name, Reason, OldState, State,
+	// Message, Commit and Time. Webhooks send the event as JSON by default.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Retries is the number of times a failed request is retried with an
+	// exponential backoff. Defaults to 3.
+	// +optional
+	Retries *int `json:"retries,omitempty"`
+
+	// Suspend stops sending notifications.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+type NotificationStatus struct {
+	// +optional
+	Conditions []genericcondition.GenericCondition `json:"conditions,omitempty"`
+
+	// LastSentTime is the time of the last successfully sent notification.
+	// +optional
+	LastSentTime *metav1.Time `json:"lastSentTime,omitempty"`
+
+	// LastEvent describes the event of the last sent notification.
+	// +optional
+	LastEvent string `json:"lastEvent,omitempty"`
+
+	// LastError is the error of the last failed notification, it is
+	// cleared by the next successful one.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Sent is the number of successfully sent notifications.
+	// +optional
+	Sent int `json:"sent,omitempty"`
+
+	// Failed is the number of notifications that failed after all retries.
+	// +optional
+	Failed int `json:"failed,omitempty"`
+}
@@ -15,6 +15,10 @@ var (
 	ClusterRegistrationNamespaceAnnotation = "fleet.cattle.io/cluster-registration-namespace"
 	ManagedLabel                           = "fleet.cattle.io/managed"
 
+	// RolloutAuditAnnotation records the 'fleet rollout' actions taken on
+	// a resource, as a JSON list of the most recent actions.
+	RolloutAuditAnnotation = "fleet.cattle.io/rollout-audit"
+
 	BootstrapToken = "fleet.cattle.io/bootstrap-token"
 )
 
@@ -70,6 +74,18 @@ type ClusterSpec struct {
 	// AgentNamespace defaults to the system namespace, e.g. cattle-fleet-system
 	AgentNamespace string `json:"agentNamespace,omitempty"`
 	PrivateRepoURL string `json:"privateRepoURL,omitempty"`
+
+	// AgentTolerations are added to the default tolerations of the
+	// agent deployment, in addition to the global ones.
+	AgentTolerations []v1.Toleration `json:"agentTolerations,omitempty"`
+	// AgentAffinity replaces the default affinity of the agent deployment.
+	AgentAffinity *v1.Affinity `json:"agentAffinity,omitempty"`
+	// AgentResources sets the resource requests and limits of the agent container.
+	AgentResources *v1.ResourceRequirements `json:"agentResources,omitempty"`
+	// AgentPriorityClassName sets the priority class of the agent pods.
+	AgentPriorityClassName string `json:"agentPriorityClassName,omitempty"`
+	// AgentReplicas is the number of agent pods, defaults to 1.
+	AgentReplicas *int32 `json:"agentReplicas,omitempty"`
 }
 
 type ClusterStatus struct {
@@ -87,6 +103,7 @@ type ClusterStatus struct {
 
 	AgentEnvVarsHash        string `json:"agentEnvVarsHash,omitempty"`
 	AgentPrivateRepoURL     string `json:"agentPrivateRepoURL,omitempty"`
+	AgentSchedulingHash     string `json:"agentSchedulingHash,omitempty"`
 	AgentDeployedGeneration *int64 `json:"agentDeployedGeneration,omitempty"`
 	AgentMigrated           bool   `json:"agentMigrated,omitempty"`
 	AgentNamespaceMigrated  bool   `json:"agentNamespaceMigrated,omitempty"`
@@ -112,6 +129,11 @@ type AgentStatus struct {
 	NonReadyNodeNames []string `json:"nonReadyNodeNames"`
 	// At most 3 nodes
 	ReadyNodeNames []string `json:"readyNodeNames"`
+	// LeaderIdentity is the pod name of the agent replica holding the
+	// leader lease
+	LeaderIdentity string `json:"leaderIdentity,omitempty"`
+	// LastLeaderTransition is when the current leader acquired the lease
+	LastLeaderTransition metav1.Time `json:"lastLeaderTransition,omitempty"`
 }
 
 // +genclient
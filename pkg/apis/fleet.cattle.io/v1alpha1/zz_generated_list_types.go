@@ -210,3 +210,20 @@ func NewImageScan(namespace, name string, obj ImageScan) *ImageScan {
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NotificationList is a list of Notification resources
+type NotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Notification `json:"items"`
+}
+
+func NewNotification(namespace, name string, obj Notification) *Notification {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("Notification").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
@@ -39,6 +39,7 @@ var (
 	GitRepoResourceName                  = "gitrepos"
 	GitRepoRestrictionResourceName       = "gitreporestrictions"
 	ImageScanResourceName                = "imagescans"
+	NotificationResourceName             = "notifications"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -84,6 +85,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GitRepoRestrictionList{},
 		&ImageScan{},
 		&ImageScanList{},
+		&Notification{},
+		&NotificationList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
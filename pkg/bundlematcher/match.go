@@ -40,6 +40,22 @@ func (a *BundleMatch) Match(clusterName string, clusterGroups map[string]map[str
 	return nil
 }
 
+// IsRestricted returns true if a target of the bundle selects the cluster,
+// but the targetRestrictions exclude it.
+func (a *BundleMatch) IsRestricted(clusterName string, clusterGroups map[string]map[string]string, clusterLabels map[string]string) bool {
+	if len(clusterGroups) == 0 {
+		return a.matcher.isRestricted(clusterName, "", nil, clusterLabels) &&
+			a.matcher.target(clusterName, "", nil, clusterLabels) != nil
+	}
+	for clusterGroup, clusterGroupLabels := range clusterGroups {
+		if a.matcher.isRestricted(clusterName, clusterGroup, clusterGroupLabels, clusterLabels) &&
+			a.matcher.target(clusterName, clusterGroup, clusterGroupLabels, clusterLabels) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 type targetMatch struct {
 	bundleTarget *fleet.BundleTarget
 	criteria     *match.ClusterMatcher
@@ -98,7 +114,12 @@ func (m *matcher) Match(clusterName, clusterGroup string, clusterGroupLabels, cl
 	if m.isRestricted(clusterName, clusterGroup, clusterGroupLabels, clusterLabels) {
 		return nil
 	}
+	return m.target(clusterName, clusterGroup, clusterGroupLabels, clusterLabels)
+}
 
+// target returns the first target selecting the cluster, regardless of the
+// restrictions.
+func (m *matcher) target(clusterName, clusterGroup string, clusterGroupLabels, clusterLabels map[string]string) *fleet.BundleTarget {
 	for _, targetMatch := range m.matches {
 		if targetMatch.criteria.Match(clusterName, clusterGroup, clusterGroupLabels, clusterLabels) {
 			return targetMatch.bundleTarget
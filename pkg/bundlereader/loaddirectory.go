@@ -57,6 +57,21 @@ func loadDirectory(ctx context.Context, compress bool, prefix, base, source, ver
 	return resources, nil
 }
 
+// FetchError is returned if the content of a bundle, e.g. a helm chart,
+// couldn't be downloaded.
+type FetchError struct {
+	Source string
+	Err    error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("failed to fetch %s: %v", e.Source, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
 // getContent uses go-getter (and helm for oci) to read the files from directories and servers
 func getContent(ctx context.Context, base, source, version string, auth Auth) (map[string][]byte, error) {
 	temp, err := os.MkdirTemp("", "fleet")
@@ -73,7 +88,7 @@ func getContent(ctx context.Context, base, source, version string, auth Auth) (m
 	if hasOCIURL.MatchString(source) {
 		source, err = downloadOCIChart(source, version, temp, auth)
 		if err != nil {
-			return nil, err
+			return nil, &FetchError{Source: orgSource, Err: err}
 		}
 	}
 
@@ -115,7 +130,7 @@ func getContent(ctx context.Context, base, source, version string, auth Auth) (m
 	}
 
 	if err := c.Get(); err != nil {
-		return nil, err
+		return nil, &FetchError{Source: orgSource, Err: err}
 	}
 
 	files := map[string][]byte{}
@@ -31,6 +31,9 @@ type Options struct {
 	Paused          bool
 	SyncGeneration  int64
 	Auth            Auth
+	// Targets holds targets and target restrictions to append, like the
+	// ones of the TargetsFile, which takes precedence
+	Targets *fleet.BundleSpec
 }
 
 // Open reads the fleet.yaml, from stdin, or basedir, or a file in basedir.
@@ -126,19 +129,6 @@ func size(bundle *fleet.Bundle) (int, error) {
 	return len(marshalled), nil
 }
 
-type fleetYAML struct {
-	Name   string            `json:"name,omitempty"`
-	Labels map[string]string `json:"labels,omitempty"`
-	fleet.BundleSpec
-	TargetCustomizations []fleet.BundleTarget `json:"targetCustomizations,omitempty"`
-	ImageScans           []imageScan          `json:"imageScans,omitempty"`
-}
-
-type imageScan struct {
-	Name string `json:"name,omitempty"`
-	fleet.ImageScanSpec
-}
-
 // read reads the fleet.yaml from the bundleSpecReader and loads all resources
 func read(ctx context.Context, name, baseDir string, bundleSpecReader io.Reader, opts *Options) (*fleet.Bundle, []*fleet.ImageScan, error) {
 	if opts == nil {
@@ -154,7 +144,7 @@ func read(ctx context.Context, name, baseDir string, bundleSpecReader io.Reader,
 		return nil, nil, err
 	}
 
-	fy := &fleetYAML{}
+	fy := &fleetyaml.FleetYAML{}
 	if err := yaml.Unmarshal(bytes, fy); err != nil {
 		return nil, nil, err
 	}
@@ -168,11 +158,13 @@ func read(ctx context.Context, name, baseDir string, bundleSpecReader io.Reader,
 			return nil, nil, errors.New("the name of scan is required")
 		}
 
+		spec := scan.ImageScanSpec
+		spec.Fields = scanFields(baseDir, spec.Fields)
 		scans = append(scans, &fleet.ImageScan{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: name1.SafeConcatName("imagescan", name, strconv.Itoa(i)),
 			},
-			Spec: scan.ImageScanSpec,
+			Spec: spec,
 		})
 	}
 
@@ -225,7 +217,7 @@ func read(ctx context.Context, name, baseDir string, bundleSpecReader io.Reader,
 
 	bundle.Spec.ForceSyncGeneration = opts.SyncGeneration
 
-	bundle, err = appendTargets(bundle, opts.TargetsFile)
+	bundle, err = appendTargets(bundle, opts.TargetsFile, opts.Targets)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -277,19 +269,20 @@ func propagateHelmChartProperties(spec *fleet.BundleSpec) {
 	}
 }
 
-func appendTargets(def *fleet.Bundle, targetsFile string) (*fleet.Bundle, error) {
-	if targetsFile == "" {
-		return def, nil
-	}
+func appendTargets(def *fleet.Bundle, targetsFile string, spec *fleet.BundleSpec) (*fleet.Bundle, error) {
+	if targetsFile != "" {
+		data, err := os.ReadFile(targetsFile)
+		if err != nil {
+			return nil, err
+		}
 
-	data, err := os.ReadFile(targetsFile)
-	if err != nil {
-		return nil, err
+		spec = &fleet.BundleSpec{}
+		if err := yaml.Unmarshal(data, spec); err != nil {
+			return nil, err
+		}
 	}
-
-	spec := &fleet.BundleSpec{}
-	if err := yaml.Unmarshal(data, spec); err != nil {
-		return nil, err
+	if spec == nil {
+		return def, nil
 	}
 
 	def.Spec.Targets = append(def.Spec.Targets, spec.Targets...)
@@ -314,3 +307,29 @@ func readMetadata(bytes []byte) (*bundleMeta, error) {
 	temp := &bundleMeta{}
 	return temp, yaml.Unmarshal(bytes, temp)
 }
+
+// scanFields makes the files of the fields relative to the root of the git
+// repository, instead of the fleet.yaml. Fields without file are in the
+// fleet.yaml.
+func scanFields(baseDir string, fields []fleet.ImageScanField) []fleet.ImageScanField {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	fleetYAML := fleetyaml.GetFleetYamlPath(baseDir, false)
+	if _, err := os.Stat(fleetYAML); os.IsNotExist(err) {
+		fleetYAML = fleetyaml.GetFleetYamlPath(baseDir, true)
+	}
+
+	result := make([]fleet.ImageScanField, 0, len(fields))
+	for _, field := range fields {
+		if field.File == "" {
+			field.File = fleetYAML
+		} else {
+			field.File = filepath.Join(baseDir, field.File)
+		}
+		field.File = filepath.ToSlash(field.File)
+		result = append(result, field)
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package bundlereader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenMetadata(t *testing.T) {
+	dir := t.TempDir()
+	fleetYAML := `name: app
+labels:
+  team: web
+defaultNamespace: app
+`
+	if err := os.WriteFile(filepath.Join(dir, "fleet.yaml"), []byte(fleetYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cm.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, _, err := Open(context.Background(), "repo-app", dir, "", &Options{Labels: map[string]string{"fleet.cattle.io/repo-name": "repo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Name != "app" {
+		t.Errorf("expected the name of the fleet.yaml, got %s", bundle.Name)
+	}
+	if bundle.Labels["team"] != "web" || bundle.Labels["fleet.cattle.io/repo-name"] != "repo" {
+		t.Errorf("unexpected labels %v", bundle.Labels)
+	}
+}
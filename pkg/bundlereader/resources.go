@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/rancher/fleet/modules/cli/pkg/progress"
@@ -157,7 +158,11 @@ func addRemoteCharts(directories []directory, base string, charts []*fleet.HelmO
 		if _, err := os.Stat(filepath.Join(base, chart.Chart)); os.IsNotExist(err) || chart.Repo != "" {
 			chartURL, err := chartURL(chart, auth)
 			if err != nil {
-				return nil, err
+				source := chart.Chart
+				if chart.Repo != "" {
+					source = strings.TrimSuffix(chart.Repo, "/") + "/" + chart.Chart
+				}
+				return nil, &FetchError{Source: source, Err: err}
 			}
 
 			directories = append(directories, directory{
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/rancher/fleet/pkg/crd"
+	"github.com/rancher/fleet/pkg/fleetyaml"
 	controllergen "github.com/rancher/wrangler/pkg/controller-gen"
 	"github.com/rancher/wrangler/pkg/controller-gen/args"
 
@@ -21,6 +22,14 @@ func main() {
 		return
 	}
 
+	if len(os.Args) > 2 && os.Args[1] == "fleetyaml-schema" {
+		fmt.Println("Writing fleet.yaml schema to", os.Args[2])
+		if err := fleetyaml.WriteSchemaFile(os.Args[2]); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	os.Unsetenv("GOPATH")
 	controllergen.Run(args.Options{
 		OutputPackage: "github.com/rancher/fleet/pkg/generated",
@@ -49,6 +49,23 @@ type Config struct {
 	APIServerCA                     []byte            `json:"apiServerCA,omitempty"`
 	Bootstrap                       Bootstrap         `json:"bootstrap,omitempty"`
 	IgnoreClusterRegistrationLabels bool              `json:"ignoreClusterRegistrationLabels,omitempty"`
+
+	// Global scheduling settings for managed agents, the settings on a
+	// cluster take precedence.
+	AgentTolerations       []v1.Toleration          `json:"agentTolerations,omitempty"`
+	AgentAffinity          *v1.Affinity             `json:"agentAffinity,omitempty"`
+	AgentResources         *v1.ResourceRequirements `json:"agentResources,omitempty"`
+	AgentPriorityClassName string                   `json:"agentPriorityClassName,omitempty"`
+	AgentReplicas          *int32                   `json:"agentReplicas,omitempty"`
+
+	// ImageScanConcurrency is the number of concurrent requests of all
+	// ImageScans to image registries
+	ImageScanConcurrency int `json:"imageScanConcurrency,omitempty"`
+	// ImageScanRegistryQPS limits the requests per second to each registry
+	ImageScanRegistryQPS float64 `json:"imageScanRegistryQPS,omitempty"`
+	// ImageSyncWorkers is the number of GitRepos, which are updated by
+	// image scans at the same time
+	ImageSyncWorkers int `json:"imageSyncWorkers,omitempty"`
 }
 
 type Bootstrap struct {
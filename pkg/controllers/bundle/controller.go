@@ -10,22 +10,28 @@ import (
 	"github.com/sirupsen/logrus"
 
 	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlereader"
+	"github.com/rancher/fleet/pkg/events"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/helmdeployer"
 	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/options"
 	"github.com/rancher/fleet/pkg/summary"
 	"github.com/rancher/fleet/pkg/target"
 
 	"github.com/rancher/wrangler/pkg/apply"
 	"github.com/rancher/wrangler/pkg/generic"
+	"github.com/rancher/wrangler/pkg/kv"
 	"github.com/rancher/wrangler/pkg/relatedresource"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -39,11 +45,13 @@ type handler struct {
 	bundles           fleetcontrollers.BundleController
 	bundleDeployments fleetcontrollers.BundleDeploymentController
 	mapper            meta.RESTMapper
+	recorder          record.EventRecorder
 }
 
 func Register(ctx context.Context,
 	apply apply.Apply,
 	mapper meta.RESTMapper,
+	recorder record.EventRecorder,
 	targets *target.Manager,
 	bundles fleetcontrollers.BundleController,
 	clusters fleetcontrollers.ClusterController,
@@ -53,6 +61,7 @@ func Register(ctx context.Context,
 ) {
 	h := &handler{
 		mapper:            mapper,
+		recorder:          recorder,
 		targets:           targets,
 		bundles:           bundles,
 		bundleDeployments: bundleDeployments,
@@ -74,6 +83,7 @@ func Register(ctx context.Context,
 		})
 
 	relatedresource.Watch(ctx, "app", h.resolveApp, bundles, bundleDeployments)
+	relatedresource.Watch(ctx, "imagescan-override", h.resolveImageScan, bundles, images)
 	clusters.OnChange(ctx, "app", h.OnClusterChange)
 	bundles.OnChange(ctx, "bundle-orphan", h.OnPurgeOrphaned)
 	images.OnChange(ctx, "imagescan-orphan", h.OnPurgeOrphanedImageScan)
@@ -132,6 +142,8 @@ func (h *handler) OnClusterChange(_ string, cluster *fleet.Cluster) (*fleet.Clus
 
 func (h *handler) OnPurgeOrphaned(key string, bundle *fleet.Bundle) (*fleet.Bundle, error) {
 	if bundle == nil {
+		ns, name := kv.Split(key, "/")
+		metrics.DeleteSummaryState(metrics.BundleState, ns, name)
 		return bundle, nil
 	}
 	logrus.Debugf("OnPurgeOrphaned for bundle '%s' change, checking if gitrepo still exists", bundle.Name)
@@ -157,16 +169,25 @@ func (h *handler) OnPurgeOrphanedImageScan(key string, image *fleet.ImageScan) (
 	}
 	logrus.Debugf("OnPurgeOrphanedImageScan for image '%s' change, checking if gitrepo still exists", image.Name)
 
-	repo := image.Spec.GitRepoName
+	repos := []string{image.Spec.GitRepoName}
+	if len(image.Spec.GitRepos) > 0 {
+		repos = nil
+		for _, ref := range image.Spec.GitRepos {
+			repos = append(repos, ref.Name)
+		}
+	}
 
-	_, err := h.gitRepo.Get(image.Namespace, repo)
-	if apierrors.IsNotFound(err) {
-		return nil, h.images.Delete(image.Namespace, image.Name, nil)
-	} else if err != nil {
-		return nil, err
+	// the scan is only orphaned once all its gitrepos are gone
+	for _, repo := range repos {
+		_, err := h.gitRepo.Get(image.Namespace, repo)
+		if err == nil {
+			return image, nil
+		} else if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
 	}
 
-	return image, nil
+	return nil, h.images.Delete(image.Namespace, image.Name, nil)
 }
 
 func (h *handler) OnBundleChange(bundle *fleet.Bundle, status fleet.BundleStatus) ([]runtime.Object, fleet.BundleStatus, error) {
@@ -178,6 +199,22 @@ func (h *handler) OnBundleChange(bundle *fleet.Bundle, status fleet.BundleStatus
 		return nil, status, err
 	}
 
+	// the latest tags of image scans in override mode are set in the
+	// options, instead of being committed to git
+	scans, err := h.overrideImageScans(bundle)
+	if err != nil {
+		return nil, status, err
+	}
+	status.ImageOverrides = nil
+	if len(scans) > 0 {
+		bundle = bundle.DeepCopy()
+		style := bundlereader.DetermineStyle(manifest, bundle.Spec.BundleDeploymentOptions)
+		status.ImageOverrides, err = applyImageOverrides(&bundle.Spec.BundleDeploymentOptions, style, scans)
+		imageOverriddenCond.SetError(&status, "", err)
+	} else if imageOverriddenCond.GetStatus(&status) != "" {
+		imageOverriddenCond.SetError(&status, "", nil)
+	}
+
 	// this does not need to happen after merging the
 	// BundleDeploymentOptions, since 'fleet apply' already put the right
 	// resources into bundle.Spec.Resources
@@ -211,12 +248,41 @@ func (h *handler) OnBundleChange(bundle *fleet.Bundle, status fleet.BundleStatus
 		status.Summary.Ready,
 		status.Summary.DesiredReady)
 	status.Display.State = string(summary.GetSummaryState(status.Summary))
+	metrics.SetSummaryState(metrics.BundleState, bundle.Namespace, bundle.Name, status.Summary)
+	h.recordEvents(bundle, status)
 
 	logrus.Debugf("OnBundleChange for bundle '%s' took %s", bundle.Name, elapsed)
 
 	return objs, status, nil
 }
 
+// recordEvents emits events for changes of the bundle state and for
+// rollouts stopped by unavailable partitions.
+func (h *handler) recordEvents(bundle *fleet.Bundle, status fleet.BundleStatus) {
+	events.StateChanged(h.recorder, bundle, bundle.Status.Display.State, status.Display.State, summaryMessage(status.Summary))
+
+	wasBlocked := bundle.Status.UnavailablePartitions > bundle.Status.MaxUnavailablePartitions
+	blocked := status.UnavailablePartitions > status.MaxUnavailablePartitions
+	switch {
+	case blocked && !wasBlocked:
+		h.recorder.Eventf(bundle, corev1.EventTypeWarning, events.ReasonPartitionUnavailable,
+			"Rollout paused, %d partitions are unavailable, maxUnavailablePartitions is %d",
+			status.UnavailablePartitions, status.MaxUnavailablePartitions)
+	case !blocked && wasBlocked:
+		h.recorder.Event(bundle, corev1.EventTypeNormal, events.ReasonPartitionAvailable, "Rollout resumed")
+	}
+}
+
+// summaryMessage returns the message of the first non-ready resource.
+func summaryMessage(summary fleet.BundleSummary) string {
+	for _, nonReady := range summary.NonReadyResources {
+		if nonReady.Message != "" {
+			return nonReady.Name + ": " + nonReady.Message
+		}
+	}
+	return ""
+}
+
 func (h *handler) isNamespaced(gvk schema.GroupVersionKind) bool {
 	mapping, err := h.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
@@ -393,6 +459,7 @@ func updateTarget(t *target.Target, status *fleet.BundleStatus, partitionStatus
 		}
 		t.Deployment.Spec.DeploymentID = t.Deployment.Spec.StagedDeploymentID
 		t.Deployment.Spec.Options = t.Deployment.Spec.StagedOptions
+		metrics.BundleRollouts.WithLabelValues(t.Bundle.Namespace, t.Bundle.Name).Inc()
 	}
 }
 
@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlereader"
+	"github.com/rancher/fleet/pkg/options"
+
+	"github.com/rancher/wrangler/pkg/condition"
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/relatedresource"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const bundleNameLabel = "fleet.cattle.io/bundle-name"
+
+// imageOverriddenCond reports if the image overrides apply to the bundle.
+var imageOverriddenCond = condition.Cond("ImageOverridden")
+
+// resolveImageScan enqueues the bundles an ImageScan in override mode
+// applies to, when its latest tag changes.
+func (h *handler) resolveImageScan(namespace, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	scan, ok := obj.(*fleet.ImageScan)
+	if !ok || scan.Spec.Mode != fleet.ImageScanModeOverride || scan.Spec.GitRepoName == "" {
+		return nil, nil
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{fleet.RepoLabel: scan.Spec.GitRepoName})
+	bundles, err := h.bundles.Cache().List(namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []relatedresource.Key
+	for _, bundle := range bundles {
+		if appliesTo(scan, bundle) {
+			keys = append(keys, relatedresource.Key{Namespace: bundle.Namespace, Name: bundle.Name})
+		}
+	}
+	return keys, nil
+}
+
+// overrideImageScans returns the scans in override mode of the bundle's
+// GitRepo, which have found a tag.
+func (h *handler) overrideImageScans(bundle *fleet.Bundle) ([]*fleet.ImageScan, error) {
+	repo := bundle.Labels[fleet.RepoLabel]
+	if repo == "" {
+		return nil, nil
+	}
+
+	scans, err := h.images.Cache().List(bundle.Namespace, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*fleet.ImageScan
+	for _, scan := range scans {
+		if scan.Spec.Mode == fleet.ImageScanModeOverride && scan.Spec.GitRepoName == repo &&
+			scan.Status.LatestTag != "" && appliesTo(scan, bundle) {
+			result = append(result, scan)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// appliesTo returns true if the scan was created for the bundle by 'fleet
+// apply', or if it was created for the whole GitRepo.
+func appliesTo(scan *fleet.ImageScan, bundle *fleet.Bundle) bool {
+	name, ok := scan.Labels[bundleNameLabel]
+	return !ok || name == bundle.Name
+}
+
+// applyImageOverrides sets the latest tags of the scans in the options, as
+// helm value of a helm chart or as kustomize image of a kustomization. The
+// options of targets are merged later and take precedence. Only overrides,
+// which apply to the style of the bundle, are returned, the others result in
+// an error.
+func applyImageOverrides(opts *fleet.BundleDeploymentOptions, style bundlereader.Style, scans []*fleet.ImageScan) ([]fleet.ImageOverride, error) {
+	var (
+		result []fleet.ImageOverride
+		errs   []string
+	)
+	for _, scan := range scans {
+		applied := false
+		if scan.Spec.HelmValue != "" && style.IsHelm() {
+			if opts.Helm == nil {
+				opts.Helm = &fleet.HelmOptions{}
+			}
+			if opts.Helm.Values == nil {
+				opts.Helm.Values = &fleet.GenericMap{}
+			}
+			if opts.Helm.Values.Data == nil {
+				opts.Helm.Values.Data = map[string]interface{}{}
+			}
+			data.PutValue(opts.Helm.Values.Data, scan.Status.LatestTag, strings.Split(scan.Spec.HelmValue, ".")...)
+			applied = true
+		}
+
+		image := scan.Spec.KustomizeImage
+		if image == "" && scan.Spec.HelmValue == "" {
+			image = scan.Spec.Image
+		}
+		if image != "" && style.IsKustomize() {
+			*opts = options.Merge(*opts, fleet.BundleDeploymentOptions{
+				Kustomize: &fleet.KustomizeOptions{
+					Images: []fleet.KustomizeImage{{Name: image, NewTag: scan.Status.LatestTag}},
+				},
+			})
+			applied = true
+		}
+
+		if !applied {
+			errs = append(errs, fmt.Sprintf("imagescan %s can't override the image, the bundle needs a helm chart for a helmValue or a kustomization.yaml", scan.Name))
+			continue
+		}
+		result = append(result, fleet.ImageOverride{
+			ImageScan: scan.Name,
+			Image:     scan.Status.CanonicalImageName,
+			Tag:       scan.Status.LatestTag,
+			Digest:    scan.Status.LatestDigest,
+		})
+	}
+	if len(errs) > 0 {
+		return result, errors.New(strings.Join(errs, "; "))
+	}
+	return result, nil
+}
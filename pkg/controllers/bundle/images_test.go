@@ -0,0 +1,212 @@
+package bundle
+
+import (
+	"reflect"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/bundlereader"
+	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/relatedresource"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type bundleController struct {
+	fleetcontrollers.BundleController
+	bundles []*fleet.Bundle
+}
+
+func (b bundleController) Cache() fleetcontrollers.BundleCache {
+	return bundleCache{bundles: b.bundles}
+}
+
+type bundleCache struct {
+	fleetcontrollers.BundleCache
+	bundles []*fleet.Bundle
+}
+
+func (b bundleCache) List(namespace string, selector labels.Selector) (result []*fleet.Bundle, err error) {
+	for _, bundle := range b.bundles {
+		if bundle.Namespace == namespace && selector.Matches(labels.Set(bundle.Labels)) {
+			result = append(result, bundle)
+		}
+	}
+	return result, nil
+}
+
+type imageScanController struct {
+	fleetcontrollers.ImageScanController
+	scans []*fleet.ImageScan
+}
+
+func (i imageScanController) Cache() fleetcontrollers.ImageScanCache {
+	return imageScanCache{scans: i.scans}
+}
+
+type imageScanCache struct {
+	fleetcontrollers.ImageScanCache
+	scans []*fleet.ImageScan
+}
+
+func (i imageScanCache) List(namespace string, selector labels.Selector) (result []*fleet.ImageScan, err error) {
+	for _, scan := range i.scans {
+		if scan.Namespace == namespace && selector.Matches(labels.Set(scan.Labels)) {
+			result = append(result, scan)
+		}
+	}
+	return result, nil
+}
+
+func newBundle(name, repo string) *fleet.Bundle {
+	return &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fleet-local",
+			Name:      name,
+			Labels:    map[string]string{fleet.RepoLabel: repo},
+		},
+	}
+}
+
+func newScan(name, repo, bundle string) *fleet.ImageScan {
+	scan := &fleet.ImageScan{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fleet-local",
+			Name:      name,
+		},
+		Spec: fleet.ImageScanSpec{
+			GitRepoName: repo,
+			Image:       "example.com/app",
+			Mode:        fleet.ImageScanModeOverride,
+		},
+		Status: fleet.ImageScanStatus{
+			LatestTag:          "1.1.0",
+			CanonicalImageName: "example.com/app",
+		},
+	}
+	if bundle != "" {
+		scan.Labels = map[string]string{bundleNameLabel: bundle}
+	}
+	return scan
+}
+
+func TestResolveImageScan(t *testing.T) {
+	h := &handler{
+		bundles: bundleController{bundles: []*fleet.Bundle{
+			newBundle("repo-app", "repo"),
+			newBundle("repo-db", "repo"),
+			newBundle("other-app", "other"),
+		}},
+	}
+
+	keys, err := h.resolveImageScan("fleet-local", "", newScan("app", "repo", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []relatedresource.Key{
+		{Namespace: "fleet-local", Name: "repo-app"},
+		{Namespace: "fleet-local", Name: "repo-db"},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected the bundles of the gitrepo %v, got %v", expected, keys)
+	}
+
+	keys, err = h.resolveImageScan("fleet-local", "", newScan("app", "repo", "repo-db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].Name != "repo-db" {
+		t.Errorf("expected the bundle the scan was created for, got %v", keys)
+	}
+
+	commit := newScan("app", "repo", "")
+	commit.Spec.Mode = ""
+	if keys, _ := h.resolveImageScan("fleet-local", "", commit); len(keys) != 0 {
+		t.Errorf("expected no bundles for a scan in commit mode, got %v", keys)
+	}
+}
+
+func TestOverrideImageScans(t *testing.T) {
+	noTag := newScan("no-tag", "repo", "")
+	noTag.Status.LatestTag = ""
+	commit := newScan("commit", "repo", "")
+	commit.Spec.Mode = ""
+	h := &handler{
+		images: imageScanController{scans: []*fleet.ImageScan{
+			newScan("web", "repo", ""),
+			newScan("app", "repo", "repo-app"),
+			newScan("db", "repo", "repo-db"),
+			newScan("other", "other", ""),
+			noTag,
+			commit,
+		}},
+	}
+
+	scans, err := h.overrideImageScans(newBundle("repo-app", "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, scan := range scans {
+		names = append(names, scan.Name)
+	}
+	if expected := []string{"app", "web"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected scans %v, got %v", expected, names)
+	}
+
+	if scans, _ := h.overrideImageScans(newBundle("manual", "")); len(scans) != 0 {
+		t.Errorf("expected no scans for a bundle without gitrepo, got %v", scans)
+	}
+}
+
+func TestApplyImageOverrides(t *testing.T) {
+	helm := newScan("helm", "repo", "")
+	helm.Spec.HelmValue = "image.tag"
+	kustomize := newScan("kustomize", "repo", "")
+
+	opts := fleet.BundleDeploymentOptions{}
+	overrides, err := applyImageOverrides(&opts, bundlereader.Style{HasChartYAML: true}, []*fleet.ImageScan{helm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 1 || overrides[0].Tag != "1.1.0" {
+		t.Errorf("expected the helm override, got %v", overrides)
+	}
+	image := opts.Helm.Values.Data["image"].(map[string]interface{})
+	if image["tag"] != "1.1.0" {
+		t.Errorf("expected the helm value to be set, got %v", opts.Helm.Values.Data)
+	}
+	if opts.Kustomize != nil {
+		t.Errorf("expected no kustomize images, got %v", opts.Kustomize.Images)
+	}
+
+	opts = fleet.BundleDeploymentOptions{}
+	overrides, err = applyImageOverrides(&opts, bundlereader.Style{KustomizePath: "kustomization.yaml"}, []*fleet.ImageScan{kustomize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []fleet.KustomizeImage{{Name: "example.com/app", NewTag: "1.1.0"}}
+	if len(overrides) != 1 || !reflect.DeepEqual(opts.Kustomize.Images, expected) {
+		t.Errorf("expected the kustomize image %v, got %v", expected, opts.Kustomize)
+	}
+
+	// a helm chart without kustomization.yaml can't take a kustomize
+	// image, raw YAML can't take any override
+	for _, style := range []bundlereader.Style{{HasChartYAML: true}, {}} {
+		opts = fleet.BundleDeploymentOptions{}
+		overrides, err = applyImageOverrides(&opts, style, []*fleet.ImageScan{helm, kustomize})
+		if err == nil {
+			t.Errorf("expected an error for the style %+v", style)
+		}
+		for _, override := range overrides {
+			if override.ImageScan == "kustomize" {
+				t.Errorf("expected the kustomize override not to be recorded for the style %+v", style)
+			}
+		}
+		if style.IsRawYAML() && (len(overrides) != 0 || !reflect.DeepEqual(opts, fleet.BundleDeploymentOptions{})) {
+			t.Errorf("expected raw YAML not to be changed, got %v %+v", overrides, opts)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/rancher/fleet/pkg/controllers/clusterregistration"
 	"github.com/rancher/fleet/pkg/durations"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/summary"
 
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
@@ -75,7 +76,9 @@ func Register(ctx context.Context,
 func (h *handler) ensureNSDeleted(key string, obj *fleet.Cluster) (*fleet.Cluster, error) {
 	if obj == nil {
 		logrus.Debugf("Cluster %s deleted, enqueue cluster namespace deletion", key)
-		h.namespaces.Enqueue(clusterNamespace(kv.Split(key, "/")))
+		ns, name := kv.Split(key, "/")
+		metrics.DeleteSummaryState(metrics.ClusterState, ns, name)
+		h.namespaces.Enqueue(clusterNamespace(ns, name))
 	}
 	return obj, nil
 }
@@ -196,6 +199,7 @@ func (h *handler) OnClusterChanged(cluster *fleet.Cluster, status fleet.ClusterS
 	}
 
 	summary.SetReadyConditions(&status, "Bundle", status.Summary)
+	metrics.SetSummaryState(metrics.ClusterState, cluster.Namespace, cluster.Name, status.Summary)
 	return status, h.createNamespace(cluster, status)
 }
 
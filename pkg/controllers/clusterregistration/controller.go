@@ -18,6 +18,7 @@ import (
 	"github.com/rancher/fleet/pkg/config"
 	"github.com/rancher/fleet/pkg/durations"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/registration"
 	secretutil "github.com/rancher/fleet/pkg/secret"
 
@@ -217,6 +218,7 @@ func (h *handler) OnChange(request *fleet.ClusterRegistration, status fleet.Clus
 			return nil, status, err
 		} else if secret != nil {
 			status.Granted = true
+			metrics.ClusterRegistrations.WithLabelValues(request.Namespace).Inc()
 			objects = append(objects, secret)
 		}
 	}
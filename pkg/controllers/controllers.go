@@ -19,7 +19,9 @@ import (
 	"github.com/rancher/fleet/pkg/controllers/git"
 	"github.com/rancher/fleet/pkg/controllers/image"
 	"github.com/rancher/fleet/pkg/controllers/manageagent"
+	"github.com/rancher/fleet/pkg/controllers/notification"
 	"github.com/rancher/fleet/pkg/durations"
+	"github.com/rancher/fleet/pkg/events"
 	"github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/manifest"
@@ -34,6 +36,8 @@ import (
 	"github.com/rancher/wrangler/pkg/apply"
 	"github.com/rancher/wrangler/pkg/generated/controllers/apps"
 	appscontrollers "github.com/rancher/wrangler/pkg/generated/controllers/apps/v1"
+	"github.com/rancher/wrangler/pkg/generated/controllers/batch"
+	batchcontrollers "github.com/rancher/wrangler/pkg/generated/controllers/batch/v1"
 	"github.com/rancher/wrangler/pkg/generated/controllers/core"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/pkg/generated/controllers/rbac"
@@ -48,6 +52,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -57,12 +62,14 @@ type appContext struct {
 	K8s           kubernetes.Interface
 	Core          corecontrollers.Interface
 	Apps          appscontrollers.Interface
+	Batch         batchcontrollers.Interface
 	RBAC          rbaccontrollers.Interface
 	GitJob        gitcontrollers.Interface
 	TargetManager *target.Manager
 	RESTMapper    meta.RESTMapper
 	Apply         apply.Apply
 	ClientConfig  clientcmd.ClientConfig
+	Recorder      record.EventRecorder
 	starters      []start.Starter
 	DisableGitops bool
 }
@@ -124,6 +131,7 @@ func Register(ctx context.Context, systemNamespace string, cfg clientcmd.ClientC
 	bundle.Register(ctx,
 		appCtx.Apply,
 		appCtx.RESTMapper,
+		appCtx.Recorder,
 		appCtx.TargetManager,
 		appCtx.Bundle(),
 		appCtx.Cluster(),
@@ -193,12 +201,14 @@ func Register(ctx context.Context, systemNamespace string, cfg clientcmd.ClientC
 				appCtx.Core.ConfigMap(),
 				appCtx.Core.ServiceAccount()),
 			appCtx.GitJob.GitJob(),
+			appCtx.Batch.Job(),
 			appCtx.BundleDeployment(),
 			appCtx.GitRepoRestriction().Cache(),
 			appCtx.Bundle(),
 			appCtx.ImageScan(),
 			appCtx.GitRepo(),
-			appCtx.Core.Secret().Cache())
+			appCtx.Core.Secret().Cache(),
+			appCtx.Recorder)
 	}
 
 	bootstrap.Register(ctx,
@@ -215,6 +225,7 @@ func Register(ctx context.Context, systemNamespace string, cfg clientcmd.ClientC
 		appCtx.Core.Secret().Cache())
 
 	display.Register(ctx,
+		appCtx.Recorder,
 		appCtx.Cluster(),
 		appCtx.ClusterGroup(),
 		appCtx.GitRepo(),
@@ -226,6 +237,12 @@ func Register(ctx context.Context, systemNamespace string, cfg clientcmd.ClientC
 		appCtx.GitRepo(),
 		appCtx.ImageScan())
 
+	notification.Register(ctx,
+		appCtx.Notification(),
+		appCtx.GitRepo(),
+		appCtx.Bundle(),
+		appCtx.Core.Secret().Cache())
+
 	leader.RunOrDie(ctx, systemNamespace, "fleet-controller-lock", appCtx.K8s, func(ctx context.Context) {
 		if err := appCtx.start(ctx); err != nil {
 			logrus.Fatal(err)
@@ -296,6 +313,14 @@ func newContext(cfg clientcmd.ClientConfig, disableGitops bool) (*appContext, er
 	}
 	appsv := apps.Apps().V1()
 
+	batch, err := batch.NewFactoryFromConfigWithOptions(client, &batch.FactoryOptions{
+		SharedControllerFactory: scf,
+	})
+	if err != nil {
+		return nil, err
+	}
+	batchv := batch.Batch().V1()
+
 	git, err := gitjob.NewFactoryFromConfigWithOptions(client, &gitjob.FactoryOptions{
 		SharedControllerFactory: scf,
 	})
@@ -331,6 +356,7 @@ func newContext(cfg clientcmd.ClientConfig, disableGitops bool) (*appContext, er
 		RESTMapper:    restMapper,
 		K8s:           k8s,
 		Apps:          appsv,
+		Batch:         batchv,
 		Interface:     fleetv,
 		Core:          corev,
 		RBAC:          rbacv,
@@ -338,9 +364,11 @@ func newContext(cfg clientcmd.ClientConfig, disableGitops bool) (*appContext, er
 		GitJob:        gitv,
 		TargetManager: targetManager,
 		ClientConfig:  cfg,
+		Recorder:      events.NewRecorder(k8s, "fleet-controller"),
 		starters: []start.Starter{
 			core,
 			apps,
+			batch,
 			fleet,
 			rbac,
 			git,
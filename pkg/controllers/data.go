@@ -31,6 +31,11 @@ func applyBootstrapResources(systemNamespace, systemRegistrationNamespace string
 						APIGroups: []string{fleetgroup.GroupName},
 						Resources: []string{fleet.BundleDeploymentResourceName + "/status"},
 					},
+					{
+						Verbs:     []string{"create", "patch"},
+						APIGroups: []string{""},
+						Resources: []string{"events"},
+					},
 				},
 			},
 			// used by request-* service accounts from agents
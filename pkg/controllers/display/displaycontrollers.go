@@ -7,23 +7,30 @@ import (
 	"strings"
 
 	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/events"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/summary"
 	"github.com/sirupsen/logrus"
 
 	"github.com/rancher/wrangler/pkg/genericcondition"
+
+	"k8s.io/client-go/tools/record"
 )
 
 type handler struct {
+	recorder record.EventRecorder
 }
 
 func Register(ctx context.Context,
+	recorder record.EventRecorder,
 	clusters fleetcontrollers.ClusterController,
 	clustergroups fleetcontrollers.ClusterGroupController,
 	gitrepos fleetcontrollers.GitRepoController,
 	bundledeployments fleetcontrollers.BundleDeploymentController,
 	bundles fleetcontrollers.BundleController) {
-	h := &handler{}
+	h := &handler{
+		recorder: recorder,
+	}
 
 	// NOTE these handlers have an empty "condition", so they won't update lastUpdateTime in the status
 	fleetcontrollers.RegisterClusterStatusHandler(ctx, clusters, "", "cluster-display", h.OnClusterChange)
@@ -53,6 +60,7 @@ func (h *handler) OnClusterChange(cluster *fleet.Cluster, status fleet.ClusterSt
 	if status.Agent.LastSeen.IsZero() {
 		status.Display.State = "WaitCheckIn"
 	}
+	events.StateChanged(h.recorder, cluster, cluster.Status.Display.State, status.Display.State, "")
 	return status, nil
 }
 
@@ -17,12 +17,15 @@ import (
 	"github.com/rancher/fleet/pkg/config"
 	"github.com/rancher/fleet/pkg/controllers/clusterregistration"
 	"github.com/rancher/fleet/pkg/display"
+	"github.com/rancher/fleet/pkg/events"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/summary"
+	"github.com/rancher/fleet/pkg/target"
 
 	gitjob "github.com/rancher/gitjob/pkg/apis/gitjob.cattle.io/v1"
 	v1 "github.com/rancher/gitjob/pkg/generated/controllers/gitjob.cattle.io/v1"
 	"github.com/rancher/wrangler/pkg/apply"
+	batchcontrollers "github.com/rancher/wrangler/pkg/generated/controllers/batch/v1"
 	corev1controller "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/pkg/genericcondition"
 	"github.com/rancher/wrangler/pkg/kv"
@@ -36,6 +39,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
@@ -45,12 +49,14 @@ var (
 func Register(ctx context.Context,
 	apply apply.Apply,
 	gitJobs v1.GitJobController,
+	jobs batchcontrollers.JobController,
 	bundleDeployments fleetcontrollers.BundleDeploymentController,
 	gitRepoRestrictions fleetcontrollers.GitRepoRestrictionCache,
 	bundles fleetcontrollers.BundleController,
 	images fleetcontrollers.ImageScanController,
 	gitRepos fleetcontrollers.GitRepoController,
-	secrets corev1controller.SecretCache) {
+	secrets corev1controller.SecretCache,
+	recorder record.EventRecorder) {
 	h := &handler{
 		gitjobCache:         gitJobs.Cache(),
 		bundleCache:         bundles.Cache(),
@@ -60,6 +66,7 @@ func Register(ctx context.Context,
 		gitRepoRestrictions: gitRepoRestrictions,
 		display:             display.NewFactory(bundles.Cache()),
 		secrets:             secrets,
+		recorder:            recorder,
 	}
 
 	gitRepos.OnChange(ctx, "gitjob-purge", h.DeleteOnChange)
@@ -69,6 +76,7 @@ func Register(ctx context.Context,
 	relatedresource.Watch(ctx, "gitjobs",
 		relatedresource.OwnerResolver(true, fleet.SchemeGroupVersion.String(), "GitRepo"), gitRepos, gitJobs)
 	relatedresource.Watch(ctx, "gitjobs", resolveGitRepo, gitRepos, bundles)
+	jobs.OnChange(ctx, "gitjob-metrics", newJobMetrics().OnJobChange)
 }
 
 // resolveGitRepo enqueues a GitRepo event for a bundle change
@@ -94,34 +102,13 @@ type handler struct {
 	gitRepoRestrictions fleetcontrollers.GitRepoRestrictionCache
 	bundleDeployments   fleetcontrollers.BundleDeploymentCache
 	display             *display.Factory
-}
-
-func targetsOrDefault(targets []fleet.GitTarget) []fleet.GitTarget {
-	if len(targets) == 0 {
-		return []fleet.GitTarget{
-			{
-				Name:         "default",
-				ClusterGroup: "default",
-			},
-		}
-	}
-	return targets
+	recorder            record.EventRecorder
 }
 
 // getConfig builds a config map, containing the GitTarget cluster matchers, converted to BundleTargets.
 // The BundleTargets are duplicated into TargetRestrictions.
 func (h *handler) getConfig(repo *fleet.GitRepo) (*corev1.ConfigMap, error) {
-	spec := &fleet.BundleSpec{}
-	for _, target := range targetsOrDefault(repo.Spec.Targets) {
-		spec.Targets = append(spec.Targets, fleet.BundleTarget{
-			Name:                 target.Name,
-			ClusterName:          target.ClusterName,
-			ClusterSelector:      target.ClusterSelector,
-			ClusterGroup:         target.ClusterGroup,
-			ClusterGroupSelector: target.ClusterGroupSelector,
-		})
-		spec.TargetRestrictions = append(spec.TargetRestrictions, fleet.BundleTargetRestriction(target))
-	}
+	spec := target.GitRepoTargets(repo)
 	data, err := json.Marshal(spec)
 	if err != nil {
 		return nil, err
@@ -342,6 +329,7 @@ func (h *handler) OnChange(gitrepo *fleet.GitRepo, status fleet.GitRepoStatus) (
 	if status.GitJobStatus != "Current" {
 		status.Display.State = "GitUpdating"
 	}
+	events.StateChanged(h.recorder, gitrepo, gitrepo.Status.Display.State, status.Display.State, status.Display.Message)
 
 	branch, rev := gitrepo.Spec.Branch, gitrepo.Spec.Revision
 	if branch == "" && rev == "" {
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rancher/fleet/pkg/metrics"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// jobMetrics observes the duration of the jobs created by gitjob for a
+// GitRepo. Jobs finished before the controller started are skipped, so
+// restarts don't count them again.
+type jobMetrics struct {
+	started  time.Time
+	lock     sync.Mutex
+	observed map[string]types.UID
+}
+
+func newJobMetrics() *jobMetrics {
+	return &jobMetrics{
+		started:  time.Now(),
+		observed: map[string]types.UID{},
+	}
+}
+
+func (m *jobMetrics) OnJobChange(key string, job *batchv1.Job) (*batchv1.Job, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if job == nil {
+		delete(m.observed, key)
+		return job, nil
+	}
+
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.Kind != "GitJob" || job.Status.StartTime == nil {
+		return job, nil
+	}
+	if m.observed[key] == job.UID {
+		return job, nil
+	}
+
+	result, finished := jobFinished(job)
+	if finished == nil || finished.Time.Before(m.started) {
+		return job, nil
+	}
+
+	m.observed[key] = job.UID
+	metrics.GitJobDuration.WithLabelValues(job.Namespace, owner.Name, result).
+		Observe(finished.Sub(job.Status.StartTime.Time).Seconds())
+
+	return job, nil
+}
+
+func jobFinished(job *batchv1.Job) (string, *metav1.Time) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			if job.Status.CompletionTime != nil {
+				return "succeeded", job.Status.CompletionTime
+			}
+			return "succeeded", &cond.LastTransitionTime
+		case batchv1.JobFailed:
+			return "failed", &cond.LastTransitionTime
+		}
+	}
+	return "", nil
+}
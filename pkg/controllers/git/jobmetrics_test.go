@@ -0,0 +1,121 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/rancher/fleet/pkg/metrics"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newJob(uid types.UID, start time.Time, duration time.Duration, condition batchv1.JobConditionType) *batchv1.Job {
+	controller := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fleet-local",
+			Name:      "repo-" + string(uid),
+			UID:       uid,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "GitJob", Name: "repo", Controller: &controller},
+			},
+		},
+		Status: batchv1.JobStatus{
+			StartTime: &metav1.Time{Time: start},
+		},
+	}
+	if condition != "" {
+		finished := metav1.NewTime(start.Add(duration))
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: condition, Status: corev1.ConditionTrue, LastTransitionTime: finished},
+		}
+	}
+	return job
+}
+
+func TestJobMetrics(t *testing.T) {
+	metrics.GitJobDuration.Reset()
+	m := newJobMetrics()
+	start := m.started.Add(time.Second)
+
+	// running jobs, jobs of other owners and jobs finished before the
+	// controller started are not observed
+	jobs := []*batchv1.Job{
+		newJob("running", start, 0, ""),
+		newJob("old", start.Add(-time.Hour), time.Minute, batchv1.JobComplete),
+	}
+	other := newJob("other", start, time.Minute, batchv1.JobComplete)
+	other.OwnerReferences[0].Kind = "CronJob"
+	jobs = append(jobs, other)
+	for _, job := range jobs {
+		if _, err := m.OnJobChange(job.Namespace+"/"+job.Name, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count := testutil.CollectAndCount(metrics.GitJobDuration); count != 0 {
+		t.Fatalf("expected no observations, got %d series", count)
+	}
+
+	succeeded := newJob("a", start, 45*time.Second, batchv1.JobComplete)
+	failed := newJob("b", start, 3*time.Second, batchv1.JobFailed)
+	for _, job := range []*batchv1.Job{succeeded, failed, succeeded} {
+		if _, err := m.OnJobChange(job.Namespace+"/"+job.Name, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP fleet_gitjob_duration_seconds Duration of the jobs building bundles from a git repository.
+# TYPE fleet_gitjob_duration_seconds histogram
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="5"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="10"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="30"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="60"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="120"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="300"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="600"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="1200"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="failed",le="+Inf"} 1
+fleet_gitjob_duration_seconds_sum{name="repo",namespace="fleet-local",result="failed"} 3
+fleet_gitjob_duration_seconds_count{name="repo",namespace="fleet-local",result="failed"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="5"} 0
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="10"} 0
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="30"} 0
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="60"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="120"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="300"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="600"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="1200"} 1
+fleet_gitjob_duration_seconds_bucket{name="repo",namespace="fleet-local",result="succeeded",le="+Inf"} 1
+fleet_gitjob_duration_seconds_sum{name="repo",namespace="fleet-local",result="succeeded"} 45
+fleet_gitjob_duration_seconds_count{name="repo",namespace="fleet-local",result="succeeded"} 1
+`
+	if err := testutil.CollectAndCompare(metrics.GitJobDuration, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJobFinished(t *testing.T) {
+	start := time.Now()
+	job := newJob("a", start, time.Minute, batchv1.JobComplete)
+	completion := metav1.NewTime(start.Add(30 * time.Second))
+	job.Status.CompletionTime = &completion
+	if result, finished := jobFinished(job); result != "succeeded" || !finished.Equal(&completion) {
+		t.Errorf("expected the completion time of a succeeded job, got %s %v", result, finished)
+	}
+
+	job = newJob("b", start, time.Minute, batchv1.JobFailed)
+	if result, finished := jobFinished(job); result != "failed" || !finished.Time.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected the transition time of a failed job, got %s %v", result, finished)
+	}
+
+	if result, finished := jobFinished(newJob("c", start, 0, "")); result != "" || finished != nil {
+		t.Errorf("expected a running job, got %s %v", result, finished)
+	}
+}
@@ -0,0 +1,212 @@
+package image
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/condition"
+	"github.com/rancher/wrangler/pkg/relatedresource"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// gitRepoRefs returns the GitRepos updated by the scan, gitrepoName is a
+// single reference without paths and delay.
+func gitRepoRefs(scan *v1alpha1.ImageScan) []v1alpha1.ImageScanGitRepo {
+	if len(scan.Spec.GitRepos) > 0 {
+		return scan.Spec.GitRepos
+	}
+	if scan.Spec.GitRepoName == "" {
+		return nil
+	}
+	return []v1alpha1.ImageScanGitRepo{{Name: scan.Spec.GitRepoName}}
+}
+
+func gitRepoRef(scan *v1alpha1.ImageScan, name string) (v1alpha1.ImageScanGitRepo, bool) {
+	for _, ref := range gitRepoRefs(scan) {
+		if ref.Name == name {
+			return ref, true
+		}
+	}
+	return v1alpha1.ImageScanGitRepo{}, false
+}
+
+func gitRepoStatus(scan *v1alpha1.ImageScan, name string) (v1alpha1.ImageScanGitRepoStatus, bool) {
+	for _, status := range scan.Status.GitRepos {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return v1alpha1.ImageScanGitRepoStatus{}, false
+}
+
+// recordGitRepo sets the tag and head commit of the GitRepo in the status of
+// a scan. The update time is only changed with the tag.
+func recordGitRepo(status *v1alpha1.ImageScanStatus, name, tag, head string, now metav1.Time) bool {
+	for i := range status.GitRepos {
+		entry := &status.GitRepos[i]
+		if entry.Name != name {
+			continue
+		}
+		if entry.Tag == tag && entry.Commit == head {
+			return false
+		}
+		if entry.Tag != tag {
+			entry.Tag = tag
+			entry.UpdateTime = now
+		}
+		entry.Commit = head
+		return true
+	}
+	status.GitRepos = append(status.GitRepos, v1alpha1.ImageScanGitRepoStatus{
+		Name:       name,
+		Tag:        tag,
+		Commit:     head,
+		UpdateTime: now,
+	})
+	return true
+}
+
+// scanPaths groups the scans by the paths of the git repository they
+// update.
+func scanPaths(gitrepo *v1alpha1.GitRepo, scans []*v1alpha1.ImageScan) ([]string, map[string][]*v1alpha1.ImageScan) {
+	// Checking if paths field is empty
+	// if yes, using the default value "/"
+	defaultPaths := gitrepo.Spec.Paths
+	if len(defaultPaths) == 0 {
+		defaultPaths = []string{"/"}
+	}
+
+	result := map[string][]*v1alpha1.ImageScan{}
+	for _, scan := range scans {
+		paths := defaultPaths
+		if ref, _ := gitRepoRef(scan, gitrepo.Name); len(ref.Paths) > 0 {
+			paths = ref.Paths
+		}
+		for _, path := range paths {
+			result[path] = append(result[path], scan)
+		}
+	}
+
+	paths := make([]string, 0, len(result))
+	for path := range result {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, result
+}
+
+// released looks up the GitRepo the scan's reference waits for and checks if
+// the scan can update gitrepo.
+func (h handler) released(gitrepo *v1alpha1.GitRepo, scan *v1alpha1.ImageScan) (bool, time.Time, time.Duration) {
+	ref, _ := gitRepoRef(scan, gitrepo.Name)
+	if ref.After == "" {
+		return true, time.Time{}, 0
+	}
+	after, err := h.gitrepos.Cache().Get(gitrepo.Namespace, ref.After)
+	if err != nil {
+		logrus.Debugf("imagescan %s/%s waits for gitrepo %s: %v", scan.Namespace, scan.Name, ref.After, err)
+		after = nil
+	}
+	return isReleased(scan, ref, after, time.Now())
+}
+
+// isReleased returns true if the latest tag of the scan can be committed to
+// the GitRepo of the reference, and the time it was released at. If the
+// reference has to wait for another GitRepo, that one's branch must contain
+// the latest tag, it must have deployed that commit and be ready for the
+// delay. Otherwise, the remaining delay is returned, or zero if the other
+// GitRepo isn't ready yet.
+func isReleased(scan *v1alpha1.ImageScan, ref v1alpha1.ImageScanGitRepo, after *v1alpha1.GitRepo, now time.Time) (bool, time.Time, time.Duration) {
+	if ref.After == "" {
+		return true, time.Time{}, 0
+	}
+	if after == nil {
+		return false, time.Time{}, 0
+	}
+
+	updated, ok := gitRepoStatus(scan, ref.After)
+	if !ok || updated.Tag != scan.Status.LatestTag {
+		return false, time.Time{}, 0
+	}
+	if updated.Commit == "" || after.Status.Commit != updated.Commit {
+		return false, time.Time{}, 0
+	}
+
+	ready := condition.Cond("Ready")
+	if !ready.IsTrue(after) {
+		return false, time.Time{}, 0
+	}
+	readySince := updated.UpdateTime.Time
+	if ts, err := time.Parse(time.RFC3339, ready.GetLastUpdated(after)); err == nil && ts.After(readySince) {
+		readySince = ts
+	}
+
+	var delay time.Duration
+	if ref.Delay != nil {
+		delay = ref.Delay.Duration
+	}
+	releasedAt := readySince.Add(delay)
+	if wait := releasedAt.Sub(now); wait > 0 {
+		return false, time.Time{}, wait
+	}
+	return true, releasedAt, 0
+}
+
+// isDue returns true if the scan, released at the given time, has a tag
+// which wasn't synced to the GitRepo yet. Once synced, an update which is
+// pending in the push branch is retried with the sync interval.
+func isDue(gitrepo *v1alpha1.GitRepo, scan *v1alpha1.ImageScan, releasedAt time.Time) bool {
+	if ref, _ := gitRepoRef(scan, gitrepo.Name); ref.After == "" {
+		return false
+	}
+	if updated, _ := gitRepoStatus(scan, gitrepo.Name); updated.Tag == scan.Status.LatestTag {
+		return false
+	}
+	return gitrepo.Status.LastSyncedImageScanTime.Time.Before(releasedAt)
+}
+
+// resolveImageScan queues the GitRepos a scan writes to, after its status
+// was updated. A changed digest is only seen by shouldSync once it's in the
+// cache.
+func (h handler) resolveImageScan(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	scan, ok := obj.(*v1alpha1.ImageScan)
+	if !ok || scan.Spec.Mode == v1alpha1.ImageScanModeOverride {
+		return nil, nil
+	}
+
+	var keys []relatedresource.Key
+	for _, ref := range gitRepoRefs(scan) {
+		keys = append(keys, relatedresource.Key{Namespace: namespace, Name: ref.Name})
+	}
+	return keys, nil
+}
+
+// resolveAfter enqueues the GitRepos, which wait for the changed GitRepo
+// to be updated and ready.
+func (h handler) resolveAfter(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if _, ok := obj.(*v1alpha1.GitRepo); !ok {
+		return nil, nil
+	}
+
+	scans, err := h.imagescans.Cache().List(namespace, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []relatedresource.Key
+	for _, scan := range scans {
+		for _, ref := range scan.Spec.GitRepos {
+			if ref.After == name {
+				keys = append(keys, relatedresource.Key{Namespace: namespace, Name: ref.Name})
+			}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,174 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	"github.com/rancher/wrangler/pkg/relatedresource"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScanPaths(t *testing.T) {
+	gitrepo := &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Spec:       v1alpha1.GitRepoSpec{Paths: []string{"apps"}},
+	}
+	all := &v1alpha1.ImageScan{ObjectMeta: metav1.ObjectMeta{Name: "all"}, Spec: v1alpha1.ImageScanSpec{GitRepoName: "dev"}}
+	base := &v1alpha1.ImageScan{ObjectMeta: metav1.ObjectMeta{Name: "base"}, Spec: v1alpha1.ImageScanSpec{
+		GitRepos: []v1alpha1.ImageScanGitRepo{{Name: "dev", Paths: []string{"apps/base", "infra"}}},
+	}}
+
+	paths, scans := scanPaths(gitrepo, []*v1alpha1.ImageScan{all, base})
+	if !reflect.DeepEqual(paths, []string{"apps", "apps/base", "infra"}) {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+	if len(scans["apps"]) != 1 || scans["apps"][0] != all || len(scans["infra"]) != 1 || scans["infra"][0] != base {
+		t.Errorf("unexpected scans by path %v", scans)
+	}
+}
+
+func TestIsReleased(t *testing.T) {
+	now := time.Now()
+	ready := now.Add(-10 * time.Minute)
+	scan := &v1alpha1.ImageScan{
+		Status: v1alpha1.ImageScanStatus{
+			LatestTag: "1.1.0",
+			GitRepos: []v1alpha1.ImageScanGitRepoStatus{
+				{Name: "dev", Tag: "1.1.0", Commit: "abc", UpdateTime: metav1.NewTime(now.Add(-time.Hour))},
+			},
+		},
+	}
+	dev := &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Status: v1alpha1.GitRepoStatus{
+			Commit: "abc",
+			Conditions: []genericcondition.GenericCondition{
+				{Type: "Ready", Status: "True", LastUpdateTime: ready.UTC().Format(time.RFC3339)},
+			},
+		},
+	}
+	delay := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	if ok, _, _ := isReleased(scan, v1alpha1.ImageScanGitRepo{Name: "staging"}, nil, now); !ok {
+		t.Error("expected a reference without after to be released")
+	}
+	ok, releasedAt, _ := isReleased(scan, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev", Delay: delay(5 * time.Minute)}, dev, now)
+	if !ok || releasedAt.Unix() != ready.Add(5*time.Minute).Unix() {
+		t.Errorf("expected staging to be released after dev was ready for the delay, got %v %v", ok, releasedAt)
+	}
+	ok, _, wait := isReleased(scan, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev", Delay: delay(time.Hour)}, dev, now)
+	if ok || wait < 49*time.Minute || wait > 50*time.Minute {
+		t.Errorf("expected staging to wait for the rest of the delay, got %v %v", ok, wait)
+	}
+
+	deploying := dev.DeepCopy()
+	deploying.Status.Commit = "old"
+	if ok, _, wait := isReleased(scan, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev"}, deploying, now); ok || wait != 0 {
+		t.Error("expected staging to wait until dev deployed the update")
+	}
+
+	// the update of dev is pending in a pull request, its branch doesn't
+	// contain the tag yet
+	pending := scan.DeepCopy()
+	pending.Status.GitRepos[0].Commit = ""
+	unchanged := dev.DeepCopy()
+	unchanged.Status.Commit = ""
+	if ok, _, _ := isReleased(pending, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev"}, unchanged, now); ok {
+		t.Error("expected staging to wait until the update of dev is merged")
+	}
+
+	notReady := dev.DeepCopy()
+	notReady.Status.Conditions[0].Status = "False"
+	if ok, _, _ := isReleased(scan, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev"}, notReady, now); ok {
+		t.Error("expected staging to wait until dev is ready")
+	}
+
+	newer := scan.DeepCopy()
+	newer.Status.LatestTag = "1.2.0"
+	if ok, _, _ := isReleased(newer, v1alpha1.ImageScanGitRepo{Name: "staging", After: "dev"}, dev, now); ok {
+		t.Error("expected staging to wait until dev is updated to the latest tag")
+	}
+}
+
+func TestIsDue(t *testing.T) {
+	releasedAt := time.Now().Add(-time.Minute)
+	gitrepo := &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Status:     v1alpha1.GitRepoStatus{LastSyncedImageScanTime: metav1.NewTime(releasedAt.Add(-time.Hour))},
+	}
+	scan := &v1alpha1.ImageScan{
+		Spec: v1alpha1.ImageScanSpec{GitRepos: []v1alpha1.ImageScanGitRepo{{Name: "staging", After: "dev"}}},
+		Status: v1alpha1.ImageScanStatus{
+			LatestTag: "1.1.0",
+			GitRepos:  []v1alpha1.ImageScanGitRepoStatus{{Name: "staging", Tag: "1.0.0"}},
+		},
+	}
+	if !isDue(gitrepo, scan, releasedAt) {
+		t.Error("expected a released tag to be due")
+	}
+
+	// the update is pending in the push branch, it's not recorded until the
+	// pull request is merged
+	gitrepo.Status.LastSyncedImageScanTime = metav1.Now()
+	if isDue(gitrepo, scan, releasedAt) {
+		t.Error("expected a pending update to wait for the sync interval")
+	}
+
+	gitrepo.Status.LastSyncedImageScanTime = metav1.NewTime(releasedAt.Add(-time.Hour))
+	scan.Status.GitRepos[0].Tag = "1.1.0"
+	if isDue(gitrepo, scan, releasedAt) {
+		t.Error("expected a synced tag not to be due")
+	}
+
+	scan.Spec.GitRepos[0].After = ""
+	scan.Status.GitRepos = nil
+	if isDue(gitrepo, scan, time.Time{}) {
+		t.Error("expected a reference without after to follow the sync interval")
+	}
+}
+
+func TestRecordGitRepo(t *testing.T) {
+	first := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.Now()
+	status := &v1alpha1.ImageScanStatus{}
+
+	if !recordGitRepo(status, "dev", "1.0.0", "abc", first) {
+		t.Fatal("expected the gitrepo to be added")
+	}
+	if recordGitRepo(status, "dev", "1.0.0", "abc", now) {
+		t.Error("expected no change for the same tag and commit")
+	}
+	if !recordGitRepo(status, "dev", "1.0.0", "def", now) || !status.GitRepos[0].UpdateTime.Equal(&first) {
+		t.Error("expected a new commit to keep the update time of the tag")
+	}
+	if !recordGitRepo(status, "dev", "1.1.0", "ghi", now) || !status.GitRepos[0].UpdateTime.Equal(&now) {
+		t.Error("expected a new tag to set the update time")
+	}
+	if len(status.GitRepos) != 1 {
+		t.Errorf("expected one entry, got %v", status.GitRepos)
+	}
+}
+
+func TestResolveImageScan(t *testing.T) {
+	scan := &v1alpha1.ImageScan{Spec: v1alpha1.ImageScanSpec{
+		GitRepos: []v1alpha1.ImageScanGitRepo{{Name: "dev"}, {Name: "prod", After: "dev"}},
+	}}
+	keys, err := handler{}.resolveImageScan("fleet-local", "app", scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []relatedresource.Key{{Namespace: "fleet-local", Name: "dev"}, {Namespace: "fleet-local", Name: "prod"}}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected the gitrepos of the scan %v, got %v", expected, keys)
+	}
+
+	scan.Spec.Mode = v1alpha1.ImageScanModeOverride
+	if keys, _ := (handler{}).resolveImageScan("fleet-local", "app", scan); len(keys) != 0 {
+		t.Errorf("expected no gitrepos for a scan in override mode, got %v", keys)
+	}
+}
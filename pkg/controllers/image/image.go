@@ -9,14 +9,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
-	"sync"
 	"text/template"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -28,23 +30,26 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/config"
 	"github.com/rancher/fleet/pkg/durations"
 	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/pullrequest"
 	"github.com/rancher/fleet/pkg/update"
 
 	"github.com/rancher/wrangler/pkg/condition"
 	corev1controler "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/pkg/kstatus"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/rancher/wrangler/pkg/relatedresource"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
-	lock sync.Mutex
-
 	defaultInterval = durations.DefaultImageInterval
 )
 
@@ -56,22 +61,35 @@ const (
 
 	defaultMessageTemplate = `Update from image update automation`
 
+	defaultHistoryLimit = 10
+
 	imageScanCond = "ImageScanned"
 
 	imageSyncCond = "ImageSynced"
 )
 
 func Register(ctx context.Context, core corev1controler.Interface, gitRepos fleetcontrollers.GitRepoController, images fleetcontrollers.ImageScanController) {
+	cfg := config.Get()
 	h := handler{
 		ctx:         ctx,
 		secretCache: core.Secret().Cache(),
 		gitrepos:    gitRepos,
 		imagescans:  images,
+		registry:    newRegistry(cfg.ImageScanConcurrency, cfg.ImageScanRegistryQPS),
+		workspaces:  newWorkspaces(filepath.Join(os.TempDir(), "fleet-image-sync"), cfg.ImageSyncWorkers),
 	}
+	config.OnChange(ctx, h.registry.onConfig)
+	config.OnChange(ctx, h.workspaces.onConfig)
 
 	fleetcontrollers.RegisterImageScanStatusHandler(ctx, images, imageScanCond, "image-scan", h.onChange)
 
 	fleetcontrollers.RegisterGitRepoStatusHandler(ctx, gitRepos, imageSyncCond, "image-sync", h.onChangeGitRepo)
+
+	relatedresource.Watch(ctx, "image-sync-after", h.resolveAfter, gitRepos, gitRepos)
+
+	relatedresource.Watch(ctx, "image-sync-scan", h.resolveImageScan, gitRepos, images)
+
+	gitRepos.OnChange(ctx, "image-sync-cleanup", h.cleanupGitRepo)
 }
 
 type handler struct {
@@ -79,6 +97,8 @@ type handler struct {
 	secretCache corev1controler.SecretCache
 	gitrepos    fleetcontrollers.GitRepoController
 	imagescans  fleetcontrollers.ImageScanController
+	registry    *registry
+	workspaces  *workspaces
 }
 
 func (h handler) onChange(image *v1alpha1.ImageScan, status v1alpha1.ImageScanStatus) (v1alpha1.ImageScanStatus, error) {
@@ -101,12 +121,17 @@ func (h handler) onChange(image *v1alpha1.ImageScan, status v1alpha1.ImageScanSt
 		status.CanonicalImageName = canonical
 	}
 
-	if !shouldScan(image) {
+	scan := shouldScan(image)
+	if !scan && !shouldCheckDigest(image) {
 		return status, nil
 	}
 
-	var options []remote.Option
+	var (
+		options []remote.Option
+		authKey string
+	)
 	if image.Spec.SecretRef != nil {
+		authKey = image.Namespace + "/" + image.Spec.SecretRef.Name
 		secret, err := h.secretCache.Get(image.Namespace, image.Spec.SecretRef.Name)
 		if err != nil {
 			kstatus.SetError(image, err.Error())
@@ -120,39 +145,98 @@ func (h handler) onChange(image *v1alpha1.ImageScan, status v1alpha1.ImageScanSt
 		options = append(options, remote.WithAuth(auth))
 	}
 
-	tags, err := remote.List(ref.Context(), append(options, remote.WithContext(h.ctx))...)
-	if err != nil {
-		kstatus.SetError(image, err.Error())
-		return status, err
-	}
+	if scan && image.Spec.Pin != "" {
+		status.LastScanTime = metav1.NewTime(time.Now())
+		status.LatestTag = image.Spec.Pin
+		status.LatestImage = status.CanonicalImageName + ":" + image.Spec.Pin
+	} else if scan {
+		tags, err := h.registry.listTags(h.ctx, ref.Context(), authKey, scanInterval(image), options...)
+		if err != nil {
+			kstatus.SetError(image, err.Error())
+			return status, err
+		}
 
-	status.LastScanTime = metav1.NewTime(time.Now())
+		status.LastScanTime = metav1.NewTime(time.Now())
 
-	latestTag, err := latestTag(image.Spec.Policy, tags)
-	if err != nil {
-		kstatus.SetError(image, err.Error())
-		return status, err
+		candidates, err := filterTags(image.Spec.FilterTags, tags)
+		if err != nil {
+			kstatus.SetError(image, err.Error())
+			return status, err
+		}
+
+		latestTag, count, err := latestTag(image.Spec.Policy, candidates)
+		if err != nil {
+			kstatus.SetError(image, err.Error())
+			return status, err
+		}
+
+		status.Candidates = count
+		status.LatestTag = latestTag
+		status.LatestImage = status.CanonicalImageName + ":" + latestTag
 	}
 
-	status.LatestTag = latestTag
-	status.LatestImage = status.CanonicalImageName + ":" + latestTag
-	digest, err := getDigest(status.LatestImage, options...)
-	if err != nil {
-		kstatus.SetError(image, err.Error())
-		return status, err
+	status.ObservedGeneration = image.Generation
+
+	// drop the tags of GitRepos, which were removed from the spec
+	var synced []v1alpha1.ImageScanGitRepoStatus
+	for _, entry := range status.GitRepos {
+		if _, ok := gitRepoRef(image, entry.Name); ok {
+			synced = append(synced, entry)
+		}
 	}
-	status.LatestDigest = digest
+	status.GitRepos = synced
 
-	interval := image.Spec.Interval
-	if interval.Seconds() == 0.0 {
-		interval = metav1.Duration{
-			Duration: defaultInterval,
+	// a pinned tag is rolled back to the digest it had before
+	digest := pinnedDigest(image.Spec.Pin, status.History)
+	if digest == "" {
+		digest, err = h.registry.digest(h.ctx, status.LatestImage, options...)
+		if err != nil {
+			kstatus.SetError(image, err.Error())
+			return status, err
+		}
+	}
+	status.LastDigestCheckTime = metav1.NewTime(time.Now())
+	if digest != status.LatestDigest {
+		status.LatestDigestTime = status.LastDigestCheckTime
+		// the GitRepos are queued by the image-sync-scan watch, once the
+		// new digest time is in the status
+		if image.Spec.DigestCheckInterval != nil && status.LatestDigest != "" {
+			logrus.Infof("Digest of image %s changed to %s", status.LatestImage, digest)
 		}
 	}
-	h.imagescans.EnqueueAfter(image.Namespace, image.Name, interval.Duration)
+	status.LatestDigest = digest
+	status.History = addHistory(status.History, v1alpha1.ImageScanHistory{
+		Tag:       status.LatestTag,
+		Digest:    digest,
+		FirstSeen: status.LastDigestCheckTime,
+		Pinned:    image.Spec.Pin != "",
+	}, image.Spec.HistoryLimit)
+
+	interval := scanInterval(image)
+	if check := image.Spec.DigestCheckInterval; check != nil && check.Duration > 0 && check.Duration < interval {
+		interval = check.Duration
+	}
+	h.imagescans.EnqueueAfter(image.Namespace, image.Name, interval)
 	return status, nil
 }
 
+func scanInterval(image *v1alpha1.ImageScan) time.Duration {
+	if image.Spec.Interval.Seconds() == 0.0 {
+		return defaultInterval
+	}
+	return image.Spec.Interval.Duration
+}
+
+// shouldCheckDigest returns true if the digest of the latest tag needs to be
+// checked again, before the next scan.
+func shouldCheckDigest(image *v1alpha1.ImageScan) bool {
+	check := image.Spec.DigestCheckInterval
+	if check == nil || check.Duration <= 0 || image.Status.LatestImage == "" {
+		return false
+	}
+	return time.Since(image.Status.LastDigestCheckTime.Time) >= check.Duration
+}
+
 func getDigest(image string, options ...remote.Option) (string, error) {
 	nameRef, err := name.ParseReference(image)
 	if err != nil {
@@ -170,6 +254,15 @@ func getDigest(image string, options ...remote.Option) (string, error) {
 	return digest.String(), nil
 }
 
+// cleanupGitRepo removes the working copy of a deleted GitRepo.
+func (h handler) cleanupGitRepo(key string, gitrepo *v1alpha1.GitRepo) (*v1alpha1.GitRepo, error) {
+	if gitrepo != nil {
+		return gitrepo, nil
+	}
+	ns, name := kv.Split(key, "/")
+	return nil, h.workspaces.remove(ns, name)
+}
+
 func (h handler) onChangeGitRepo(gitrepo *v1alpha1.GitRepo, status v1alpha1.GitRepoStatus) (v1alpha1.GitRepoStatus, error) {
 	if gitrepo == nil || gitrepo.DeletionTimestamp != nil {
 		return status, nil
@@ -183,7 +276,8 @@ func (h handler) onChangeGitRepo(gitrepo *v1alpha1.GitRepo, status v1alpha1.GitR
 
 	var scans []*v1alpha1.ImageScan
 	for _, scan := range imagescans {
-		if scan.Spec.GitRepoName == gitrepo.Name {
+		// scans in override mode don't write to git
+		if _, ok := gitRepoRef(scan, gitrepo.Name); ok && scan.Spec.Mode != v1alpha1.ImageScanModeOverride {
 			scans = append(scans, scan)
 		}
 	}
@@ -207,21 +301,42 @@ func (h handler) onChangeGitRepo(gitrepo *v1alpha1.GitRepo, status v1alpha1.GitR
 		return status, errors.New(strings.Join(messages, ";"))
 	}
 
-	if !shouldSync(gitrepo) {
+	// scans waiting for another GitRepo are left out, until it's ready with
+	// their latest tag for the delay
+	var (
+		released []*v1alpha1.ImageScan
+		wait     time.Duration
+		due      bool
+	)
+	for _, scan := range scans {
+		ok, releasedAt, remaining := h.released(gitrepo, scan)
+		if !ok {
+			if remaining > 0 && (wait == 0 || remaining < wait) {
+				wait = remaining
+			}
+			continue
+		}
+		if isDue(gitrepo, scan, releasedAt) {
+			due = true
+		}
+		released = append(released, scan)
+	}
+
+	if len(released) == 0 || (!due && !shouldSync(gitrepo, released)) {
+		if wait > 0 {
+			h.gitrepos.EnqueueAfter(gitrepo.Namespace, gitrepo.Name, wait)
+		}
 		return status, nil
 	}
+	scans = released
 
 	logrus.Debugf("onChangeGitRepo: gitrepo %s/%s changed, syncing repo for image scans", gitrepo.Namespace, gitrepo.Name)
 
-	lock.Lock()
-	defer lock.Unlock()
-	// todo: maybe we should preserve the dir
-	tmp, err := os.MkdirTemp("", fmt.Sprintf("%s-%s", gitrepo.Namespace, gitrepo.Name))
+	release, err := h.workspaces.acquire(h.ctx, gitrepo.Namespace, gitrepo.Name)
 	if err != nil {
-		kstatus.SetError(gitrepo, err.Error())
 		return status, err
 	}
-	defer os.RemoveAll(tmp)
+	defer release()
 
 	auth, err := h.auth(gitrepo)
 	if err != nil {
@@ -229,37 +344,43 @@ func (h handler) onChangeGitRepo(gitrepo *v1alpha1.GitRepo, status v1alpha1.GitR
 		return status, err
 	}
 
-	repo, err := gogit.PlainClone(tmp, false, &gogit.CloneOptions{
-		URL:           gitrepo.Spec.Repo,
-		Auth:          auth,
-		RemoteName:    "origin",
-		ReferenceName: plumbing.NewBranchReferenceName(gitrepo.Spec.Branch),
-		SingleBranch:  true,
-		Depth:         1,
-		Progress:      nil,
-		Tags:          gogit.NoTags,
-	})
+	dir, repo, err := h.workspaces.checkout(h.ctx, gitrepo, auth)
 	if err != nil {
 		kstatus.SetError(gitrepo, err.Error())
 		return status, err
 	}
 
-	// Checking if paths field is empty
-	// if yes, using the default value "/"
-	paths := gitrepo.Spec.Paths
-	if len(paths) == 0 {
-		paths = []string{"/"}
-	}
-
+	var result update.Result
+	paths, pathScans := scanPaths(gitrepo, scans)
 	for _, path := range paths {
-		updatePath := filepath.Join(tmp, path)
-		if err := update.WithSetters(updatePath, updatePath, scans); err != nil {
+		updatePath := filepath.Join(dir, path)
+		pathResult, err := update.WithSetters(updatePath, updatePath, pathScans[path])
+		if err != nil {
 			kstatus.SetError(gitrepo, err.Error())
 			return status, err
 		}
+		result.Merge(pathResult)
+
+		pathResult, err = update.WithFields(dir, path, pathScans[path])
+		if err != nil {
+			kstatus.SetError(gitrepo, err.Error())
+			return status, err
+		}
+		result.Merge(pathResult)
 	}
 
-	commit, err := commitAllAndPush(context.Background(), repo, auth, gitrepo.Spec.ImageScanCommit)
+	commitSpec := gitrepo.Spec.ImageScanCommit
+	message, err := commitMessage(commitSpec, commitData{GitRepo: gitrepo, Updated: result})
+	if err != nil {
+		kstatus.SetError(gitrepo, err.Error())
+		return status, err
+	}
+	signer, err := h.signer(gitrepo.Namespace, commitSpec)
+	if err != nil {
+		kstatus.SetError(gitrepo, err.Error())
+		return status, err
+	}
+	commit, err := commitAllAndPush(context.Background(), repo, auth, commitSpec, signer, message, gitrepo.Spec.Branch)
 	if err != nil {
 		kstatus.SetError(gitrepo, err.Error())
 		return status, err
@@ -267,18 +388,55 @@ func (h handler) onChangeGitRepo(gitrepo *v1alpha1.GitRepo, status v1alpha1.GitR
 	if commit != "" {
 		logrus.Infof("Repo %s, commit %s pushed", gitrepo.Spec.Repo, commit)
 	}
+	// the head of the branch, which is deployed once the GitRepo is ready.
+	// Updates pushed to another branch are only in it once the pull request
+	// is merged.
+	head := commit
+	pending := commitSpec.PushBranch != "" && commit != ""
+	if pending || head == "" {
+		ref, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", gitrepo.Spec.Branch), true)
+		if err != nil {
+			kstatus.SetError(gitrepo, err.Error())
+			return status, err
+		}
+		head = ref.Hash().String()
+	}
+	h.recordSync(gitrepo, scans, result, commit, head, pending)
+
+	if commitSpec.PullRequest != nil {
+		status.ImageUpdatePullRequest = ""
+		if commit != "" {
+			url, err := h.openPullRequest(gitrepo, message, result)
+			if err != nil {
+				kstatus.SetError(gitrepo, err.Error())
+				return status, err
+			}
+			logrus.Infof("Repo %s, pull request %s opened", gitrepo.Spec.Repo, url)
+			status.ImageUpdatePullRequest = url
+		}
+	}
 	interval := gitrepo.Spec.ImageSyncInterval
 	if interval == nil || interval.Seconds() == 0.0 {
 		interval = &metav1.Duration{
 			Duration: defaultInterval,
 		}
 	}
+	if wait > 0 && wait < interval.Duration {
+		interval = &metav1.Duration{Duration: wait}
+	}
 	status.LastSyncedImageScanTime = metav1.NewTime(time.Now())
 	h.gitrepos.EnqueueAfter(gitrepo.Namespace, gitrepo.Name, interval.Duration)
 	return status, err
 }
 
-func shouldSync(gitrepo *v1alpha1.GitRepo) bool {
+func shouldSync(gitrepo *v1alpha1.GitRepo, scans []*v1alpha1.ImageScan) bool {
+	// a mutable tag was pushed again since the last sync
+	for _, scan := range scans {
+		if scan.Spec.DigestCheckInterval != nil && scan.Status.LatestDigestTime.After(gitrepo.Status.LastSyncedImageScanTime.Time) {
+			return true
+		}
+	}
+
 	interval := gitrepo.Spec.ImageSyncInterval
 	if interval == nil || interval.Seconds() == 0.0 {
 		interval = &metav1.Duration{
@@ -292,19 +450,15 @@ func shouldSync(gitrepo *v1alpha1.GitRepo) bool {
 	return true
 }
 
-func commitAllAndPush(ctx context.Context, repo *gogit.Repository, auth transport.AuthMethod, commit v1alpha1.CommitSpec) (string, error) {
-	working, err := repo.Worktree()
-	if err != nil {
-		return "", err
-	}
-
-	status, err := working.Status()
-	if err != nil {
-		return "", err
-	} else if status.IsClean() {
-		return "", nil
-	}
+// commitData is the data of the commit message template.
+type commitData struct {
+	GitRepo *v1alpha1.GitRepo
+	// Updated lists the changed files, the objects and images updated in
+	// them and the replaced values
+	Updated update.Result
+}
 
+func commitMessage(commit v1alpha1.CommitSpec, data commitData) (string, error) {
 	msgTmpl := commit.MessageTemplate
 	if msgTmpl == "" {
 		msgTmpl = defaultMessageTemplate
@@ -314,24 +468,143 @@ func commitAllAndPush(ctx context.Context, repo *gogit.Repository, auth transpor
 		return "", err
 	}
 	buf := &strings.Builder{}
-	if err := tmpl.Execute(buf, "no data! yet"); err != nil {
+	if err := tmpl.Execute(buf, data); err != nil {
 		return "", err
 	}
+	return buf.String(), nil
+}
+
+// commitAllAndPush commits all changes of the worktree and pushes them to
+// the cloned branch. If a push branch is set, the branch is instead reset to
+// the commit, unless it already has the same content.
+func commitAllAndPush(ctx context.Context, repo *gogit.Repository, auth transport.AuthMethod, commit v1alpha1.CommitSpec, signer *commitSigner, message, branch string) (string, error) {
+	working, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	status, err := working.Status()
+	if err != nil {
+		return "", err
+	} else if status.IsClean() {
+		return "", nil
+	}
 
 	var rev plumbing.Hash
-	if rev, err = working.Commit(buf.String(), &gogit.CommitOptions{
+	options := &gogit.CommitOptions{
 		All: true,
 		Author: &object.Signature{
 			Name:  commit.AuthorName,
 			Email: commit.AuthorEmail,
 			When:  time.Now(),
 		},
-	}); err != nil {
+	}
+	if signer != nil {
+		options.SignKey = signer.gpg
+	}
+	if rev, err = working.Commit(message, options); err != nil {
 		return "", err
 	}
+	if signer != nil && signer.ssh != nil {
+		if rev, err = signSSH(repo, rev, signer.ssh); err != nil {
+			return "", err
+		}
+	}
+
+	if commit.PushBranch == "" {
+		return rev.String(), repo.PushContext(ctx, &gogit.PushOptions{
+			Auth: auth,
+		})
+	}
 
+	if commit.PushBranch == branch {
+		return "", fmt.Errorf("pushBranch %s must differ from the branch of the GitRepo", branch)
+	}
+	pushed, err := remoteTip(ctx, repo, auth, commit.PushBranch)
+	if err != nil {
+		return "", err
+	}
+	if pushed != nil {
+		head, err := repo.CommitObject(rev)
+		if err != nil {
+			return "", err
+		}
+		if head.TreeHash == pushed.TreeHash {
+			logrus.Debugf("branch %s is up to date with the image updates", commit.PushBranch)
+			return pushed.Hash.String(), nil
+		}
+	}
+
+	refSpec := fmt.Sprintf("+%s:%s", plumbing.NewBranchReferenceName(branch), plumbing.NewBranchReferenceName(commit.PushBranch))
 	return rev.String(), repo.PushContext(ctx, &gogit.PushOptions{
-		Auth: auth,
+		Auth:     auth,
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(refSpec)},
+	})
+}
+
+// remoteTip fetches the branch and returns its last commit, or nil if the
+// branch doesn't exist yet.
+func remoteTip(ctx context.Context, repo *gogit.Repository, auth transport.AuthMethod, branch string) (*object.Commit, error) {
+	remoteRef := plumbing.NewRemoteReferenceName("origin", branch)
+	err := repo.FetchContext(ctx, &gogit.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", plumbing.NewBranchReferenceName(branch), remoteRef))},
+		Auth:       auth,
+		Depth:      1,
+		Tags:       gogit.NoTags,
+	})
+	if errors.Is(err, gogit.NoMatchingRefSpecError{}) {
+		return nil, nil
+	} else if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil, err
+	}
+
+	ref, err := repo.Reference(remoteRef, true)
+	if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(ref.Hash())
+}
+
+// openPullRequest opens or updates the pull request from the push branch to
+// the branch of the GitRepo and returns its URL.
+func (h handler) openPullRequest(gitrepo *v1alpha1.GitRepo, message string, result update.Result) (string, error) {
+	commit := gitrepo.Spec.ImageScanCommit
+	spec := commit.PullRequest
+	if commit.PushBranch == "" {
+		return "", errors.New("pullRequest requires a pushBranch")
+	}
+
+	// the token defaults to the password of the git credentials
+	secretName := gitrepo.Spec.ClientSecretName
+	usernameKey, tokenKey := corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey
+	if spec.SecretRef != nil {
+		secretName = spec.SecretRef.Name
+		usernameKey, tokenKey = "username", "token"
+	}
+	secret, err := h.secretCache.Get(gitrepo.Namespace, secretName)
+	if err != nil {
+		return "", err
+	}
+	token := string(secret.Data[tokenKey])
+	if token == "" {
+		return "", fmt.Errorf("secret %s/%s has no %s for the pull request provider", gitrepo.Namespace, secretName, tokenKey)
+	}
+
+	client, err := pullrequest.NewClient(*spec, gitrepo.Spec.Repo, string(secret.Data[usernameKey]), token)
+	if err != nil {
+		return "", err
+	}
+
+	title := spec.Title
+	if title == "" {
+		title, _, _ = strings.Cut(message, "\n")
+	}
+	return client.Open(h.ctx, pullrequest.PullRequest{
+		Title: title,
+		Body:  pullrequest.Body(gitrepo.Namespace+"/"+gitrepo.Name, result.Changes),
+		Head:  commit.PushBranch,
+		Base:  gitrepo.Spec.Branch,
 	})
 }
 
@@ -391,7 +664,7 @@ func shouldScan(image *v1alpha1.ImageScan) bool {
 			Duration: defaultInterval,
 		}
 	}
-	if image.Status.LatestTag == "" {
+	if image.Status.LatestTag == "" || image.Generation != image.Status.ObservedGeneration {
 		return true
 	}
 
@@ -401,13 +674,147 @@ func shouldScan(image *v1alpha1.ImageScan) bool {
 	return true
 }
 
-func latestTag(policy v1alpha1.ImagePolicyChoice, versions []string) (string, error) {
-	if len(versions) == 0 {
-		return "", errors.New("no tag found")
+// addHistory prepends the entry to the history, unless it's the same tag and
+// digest as the most recent entry. The history is limited to limit entries.
+func addHistory(history []v1alpha1.ImageScanHistory, entry v1alpha1.ImageScanHistory, limit int) []v1alpha1.ImageScanHistory {
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if len(history) > 0 && history[0].Tag == entry.Tag && history[0].Digest == entry.Digest {
+		return history
+	}
+	history = append([]v1alpha1.ImageScanHistory{entry}, history...)
+	if len(history) > limit {
+		history = history[:limit]
+	}
+	return history
+}
+
+// pinnedDigest returns the digest of the pinned tag from the history.
+func pinnedDigest(pin string, history []v1alpha1.ImageScanHistory) string {
+	if pin == "" {
+		return ""
+	}
+	for _, entry := range history {
+		if entry.Tag == pin && entry.Digest != "" {
+			return entry.Digest
+		}
+	}
+	return ""
+}
+
+// recordSync adds the commit to the most recent history entry of the scans,
+// which were updated by it, and records the tag synced to the GitRepo. If
+// the update is pending in a pull request, the tags of the updated scans are
+// not in the GitRepo's branch yet and not recorded.
+func (h handler) recordSync(gitrepo *v1alpha1.GitRepo, scans []*v1alpha1.ImageScan, result update.Result, commit, head string, pending bool) {
+	updated := map[string]bool{}
+	for _, change := range result.Changes {
+		updated[change.ImageScan.Name] = true
+	}
+	now := metav1.Now()
+	for _, scan := range scans {
+		tag := scan.Status.LatestTag
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			obj, err := h.imagescans.Get(scan.Namespace, scan.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			changed := false
+			history := obj.Status.History
+			if commit != "" && updated[scan.Name] && len(history) > 0 && history[0].Tag == tag && history[0].Commit == "" {
+				history[0].Commit = commit
+				changed = true
+			}
+			if !(pending && updated[scan.Name]) && recordGitRepo(&obj.Status, gitrepo.Name, tag, head, now) {
+				changed = true
+			}
+			if !changed {
+				return nil
+			}
+			_, err = h.imagescans.UpdateStatus(obj)
+			return err
+		})
+		if err != nil {
+			logrus.Warnf("Failed to record sync of gitrepo %s/%s in imagescan %s: %v", gitrepo.Namespace, gitrepo.Name, scan.Name, err)
+		}
+	}
+}
+
+// tagCandidate is a tag and the value the policy orders it by.
+type tagCandidate struct {
+	tag   string
+	value string
+}
+
+// filterTags returns the tags matching the pattern of the filter, with the
+// value extracted by the filter.
+func filterTags(filter *v1alpha1.TagFilter, tags []string) ([]tagCandidate, error) {
+	var result []tagCandidate
+	if filter == nil || filter.Pattern == "" {
+		for _, tag := range tags {
+			result = append(result, tagCandidate{tag: tag, value: tag})
+		}
+		return result, nil
+	}
+
+	re, err := regexp.Compile(filter.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filterTags pattern: %w", err)
+	}
+	if err := checkExtract(re, filter.Extract); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		match := re.FindStringSubmatchIndex(tag)
+		if match == nil {
+			continue
+		}
+		value := tag
+		if filter.Extract != "" {
+			value = string(re.ExpandString(nil, filter.Extract, tag, match))
+		}
+		result = append(result, tagCandidate{tag: tag, value: value})
+	}
+	return result, nil
+}
+
+var extractRef = regexp.MustCompile(`\$(\$|\{([^}]*)\}|([a-zA-Z0-9_]+))`)
+
+// checkExtract returns an error if extract refers to a group, which is not in
+// the pattern. It would be expanded to an empty value for every tag.
+func checkExtract(re *regexp.Regexp, extract string) error {
+	names := map[string]bool{}
+	for i, name := range re.SubexpNames() {
+		names[strconv.Itoa(i)] = true
+		if name != "" {
+			names[name] = true
+		}
+	}
+	for _, match := range extractRef.FindAllStringSubmatch(extract, -1) {
+		if match[1] == "$" {
+			continue
+		}
+		group := match[2] + match[3]
+		if !names[group] {
+			return fmt.Errorf("filterTags extract refers to group %q, which is not in pattern %q", group, re.String())
+		}
+	}
+	return nil
+}
+
+// latestTag returns the latest tag according to the policy and the number
+// of candidates the policy could order.
+func latestTag(policy v1alpha1.ImagePolicyChoice, candidates []tagCandidate) (string, int, error) {
+	if len(candidates) == 0 {
+		return "", 0, errors.New("no tag found")
 	}
 	switch {
 	case policy.SemVer != nil:
-		return semverLatest(policy.SemVer.Range, versions)
+		return semverLatest(policy.SemVer.Range, candidates)
+	case policy.Numerical != nil:
+		return numericalLatest(policy.Numerical.Order, candidates)
 	case policy.Alphabetical != nil:
 		var des bool
 		if policy.Alphabetical.Order == "" {
@@ -415,41 +822,74 @@ func latestTag(policy v1alpha1.ImagePolicyChoice, versions []string) (string, er
 		} else {
 			des = policy.Alphabetical.Order == AlphabeticalOrderDesc
 		}
-		var latest string
-		for _, version := range versions {
-			if latest == "" {
-				latest = version
+		var latest tagCandidate
+		for _, candidate := range candidates {
+			if latest.tag == "" {
+				latest = candidate
 				continue
 			}
 
-			if version >= latest && des {
-				latest = version
+			if candidate.value >= latest.value && des {
+				latest = candidate
 			}
 
-			if version <= latest && !des {
-				latest = version
+			if candidate.value <= latest.value && !des {
+				latest = candidate
 			}
 		}
-		return latest, nil
+		return latest.tag, len(candidates), nil
 	default:
-		return semverLatest("*", versions)
+		return semverLatest("*", candidates)
 	}
 }
 
-func semverLatest(r string, versions []string) (string, error) {
+func semverLatest(r string, candidates []tagCandidate) (string, int, error) {
 	contraints, err := semver.NewConstraint(r)
 	if err != nil {
-		return "", err
-	}
-	var latestVersion *semver.Version
-	for _, version := range versions {
-		if ver, err := semver.NewVersion(version); err == nil {
+		return "", 0, err
+	}
+	var (
+		latestVersion *semver.Version
+		latest        string
+		count         int
+	)
+	for _, candidate := range candidates {
+		if ver, err := semver.NewVersion(candidate.value); err == nil && contraints.Check(ver) {
+			count++
 			if latestVersion == nil || ver.GreaterThan(latestVersion) {
-				if contraints.Check(ver) {
-					latestVersion = ver
-				}
+				latestVersion = ver
+				latest = candidate.tag
 			}
 		}
 	}
-	return latestVersion.Original(), nil
+	if latestVersion == nil {
+		return "", 0, fmt.Errorf("no tag matches semver range %q", r)
+	}
+	return latest, count, nil
+}
+
+// numericalLatest orders the candidates by their value as a number. In
+// ascending order, the default, the highest number is the latest.
+func numericalLatest(order string, candidates []tagCandidate) (string, int, error) {
+	asc := !strings.EqualFold(order, AlphabeticalOrderDesc)
+	var (
+		latestValue float64
+		latest      string
+		count       int
+	)
+	for _, candidate := range candidates {
+		value, err := strconv.ParseFloat(candidate.value, 64)
+		if err != nil {
+			continue
+		}
+		count++
+		if latest == "" || (asc && value > latestValue) || (!asc && value < latestValue) {
+			latestValue = value
+			latest = candidate.tag
+		}
+	}
+	if latest == "" {
+		return "", 0, errors.New("no numerical tag found")
+	}
+	return latest, count, nil
 }
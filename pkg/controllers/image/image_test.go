@@ -0,0 +1,163 @@
+package image
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/update"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"main-1a2b3c-1650000000", "main-4d5e6f-1660000000", "main-7a8b9c-165", "1.2.3-rc.4-amd64", "1.2.4-amd64", "1.3.0-arm64", "latest"}
+
+	tests := []struct {
+		name       string
+		filter     *v1alpha1.TagFilter
+		policy     v1alpha1.ImagePolicyChoice
+		latest     string
+		candidates int
+	}{
+		{
+			name:       "numerical timestamp",
+			filter:     &v1alpha1.TagFilter{Pattern: `^main-[a-f0-9]+-(?P<ts>[0-9]+)$`, Extract: "$ts"},
+			policy:     v1alpha1.ImagePolicyChoice{Numerical: &v1alpha1.NumericalPolicy{}},
+			latest:     "main-4d5e6f-1660000000",
+			candidates: 3,
+		},
+		{
+			name:       "numerical descending",
+			filter:     &v1alpha1.TagFilter{Pattern: `^main-[a-f0-9]+-(?P<ts>[0-9]+)$`, Extract: "$ts"},
+			policy:     v1alpha1.ImagePolicyChoice{Numerical: &v1alpha1.NumericalPolicy{Order: "desc"}},
+			latest:     "main-7a8b9c-165",
+			candidates: 3,
+		},
+		{
+			name:       "semver of extracted version",
+			filter:     &v1alpha1.TagFilter{Pattern: `^(?P<version>.+)-amd64$`, Extract: "$version"},
+			policy:     v1alpha1.ImagePolicyChoice{SemVer: &v1alpha1.SemVerPolicy{Range: ">=1.0.0-0"}},
+			latest:     "1.2.4-amd64",
+			candidates: 2,
+		},
+		{
+			name:       "alphabetical of filtered tags",
+			filter:     &v1alpha1.TagFilter{Pattern: `^main-`},
+			policy:     v1alpha1.ImagePolicyChoice{Alphabetical: &v1alpha1.AlphabeticalPolicy{}},
+			latest:     "main-7a8b9c-165",
+			candidates: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates, err := filterTags(test.filter, tags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			latest, count, err := latestTag(test.policy, candidates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest != test.latest || count != test.candidates {
+				t.Errorf("expected %s of %d candidates, got %s of %d", test.latest, test.candidates, latest, count)
+			}
+		})
+	}
+
+	for _, extract := range []string{"$ts", "${version}", "$2", "v$tsx"} {
+		filter := &v1alpha1.TagFilter{Pattern: `^main-[a-f0-9]+-(?P<ts>[0-9]+)$`, Extract: extract}
+		if _, err := filterTags(filter, tags); err == nil && extract != "$ts" {
+			t.Errorf("expected an error for extract %q of a group not in the pattern", extract)
+		} else if err != nil && extract == "$ts" {
+			t.Errorf("unexpected error for extract %q: %v", extract, err)
+		}
+	}
+
+	candidates, _ := filterTags(nil, []string{"latest"})
+	if _, _, err := latestTag(v1alpha1.ImagePolicyChoice{}, candidates); err == nil {
+		t.Error("expected an error if no tag matches the semver range")
+	}
+}
+
+func TestAddHistory(t *testing.T) {
+	var history []v1alpha1.ImageScanHistory
+	for _, entry := range []v1alpha1.ImageScanHistory{
+		{Tag: "1.0.0", Digest: "sha256:a"},
+		{Tag: "1.0.0", Digest: "sha256:a"},
+		{Tag: "1.1.0", Digest: "sha256:b"},
+		{Tag: "1.1.0", Digest: "sha256:c"},
+	} {
+		history = addHistory(history, entry, 2)
+	}
+
+	if len(history) != 2 || history[0].Digest != "sha256:c" || history[1].Digest != "sha256:b" {
+		t.Errorf("expected the two most recent entries, got %v", history)
+	}
+	if digest := pinnedDigest("1.1.0", history); digest != "sha256:c" {
+		t.Errorf("expected the most recent digest of the pinned tag, got %q", digest)
+	}
+	if digest := pinnedDigest("1.0.0", history); digest != "" {
+		t.Errorf("expected no digest for a tag not in the history, got %q", digest)
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	data := commitData{
+		GitRepo: &v1alpha1.GitRepo{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		Updated: update.Result{Changes: []update.Change{
+			{File: "app/deployment.yaml", Setter: "fleet:app:tag", OldValue: "1.0.0", NewValue: "1.1.0"},
+			{File: "app/deployment.yaml", Setter: "fleet:app", OldValue: "app:1.0.0", NewValue: "app:1.1.0"},
+		}},
+	}
+
+	message, err := commitMessage(v1alpha1.CommitSpec{}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message != defaultMessageTemplate {
+		t.Errorf("expected the default message, got %q", message)
+	}
+
+	message, err = commitMessage(v1alpha1.CommitSpec{
+		MessageTemplate: "Update {{.GitRepo.Name}}\n{{range .Updated.Changes}}\n{{.File}}: {{.OldTag}} -> {{.NewTag}}{{end}}",
+	}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Update apps\n\napp/deployment.yaml: 1.0.0 -> 1.1.0\napp/deployment.yaml: 1.0.0 -> 1.1.0"
+	if message != expected {
+		t.Errorf("expected %q, got %q", expected, message)
+	}
+
+	if _, err := commitMessage(v1alpha1.CommitSpec{MessageTemplate: "{{.Unknown}}"}, data); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestShouldSync(t *testing.T) {
+	synced := time.Now().Add(-time.Minute)
+	gitrepo := &v1alpha1.GitRepo{
+		Spec:   v1alpha1.GitRepoSpec{ImageSyncInterval: &metav1.Duration{Duration: time.Hour}},
+		Status: v1alpha1.GitRepoStatus{LastSyncedImageScanTime: metav1.NewTime(synced)},
+	}
+	scan := &v1alpha1.ImageScan{
+		Spec:   v1alpha1.ImageScanSpec{DigestCheckInterval: &metav1.Duration{Duration: time.Minute}},
+		Status: v1alpha1.ImageScanStatus{LatestDigestTime: metav1.NewTime(synced.Add(-time.Minute))},
+	}
+	if shouldSync(gitrepo, []*v1alpha1.ImageScan{scan}) {
+		t.Error("expected no sync within the interval")
+	}
+
+	// the tag was pushed again after the last sync
+	scan.Status.LatestDigestTime = metav1.Now()
+	if !shouldSync(gitrepo, []*v1alpha1.ImageScan{scan}) {
+		t.Error("expected a changed digest to sync before the interval")
+	}
+
+	gitrepo.Status.LastSyncedImageScanTime = metav1.NewTime(synced.Add(-time.Hour))
+	scan.Status.LatestDigestTime = metav1.NewTime(synced.Add(-2 * time.Hour))
+	if !shouldSync(gitrepo, []*v1alpha1.ImageScan{scan}) {
+		t.Error("expected a sync after the interval")
+	}
+}
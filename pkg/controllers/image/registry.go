@@ -0,0 +1,170 @@
+package image
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+
+	"github.com/rancher/fleet/pkg/config"
+	"github.com/rancher/fleet/pkg/metrics"
+)
+
+const (
+	defaultConcurrency = 4
+	defaultRegistryQPS = 1.0
+
+	// cached tag lists are dropped after this time, even if a scan with a
+	// longer interval could use them
+	cacheExpiry = 24 * time.Hour
+)
+
+// registry shares the tag lists of image repositories between scans and
+// limits the requests to registries, with a controller-wide number of
+// concurrent requests and a rate limit per registry.
+type registry struct {
+	lock     sync.Mutex
+	tags     map[string]cachedTags
+	limiters map[string]*rate.Limiter
+	sem      chan struct{}
+	qps      float64
+	group    singleflight.Group
+}
+
+type cachedTags struct {
+	tags    []string
+	fetched time.Time
+}
+
+func newRegistry(concurrency int, qps float64) *registry {
+	r := &registry{
+		tags:     map[string]cachedTags{},
+		limiters: map[string]*rate.Limiter{},
+	}
+	r.configure(concurrency, qps)
+	return r
+}
+
+// onConfig applies the limits of the fleet-controller config.
+func (r *registry) onConfig(cfg *config.Config) error {
+	r.configure(cfg.ImageScanConcurrency, cfg.ImageScanRegistryQPS)
+	return nil
+}
+
+func (r *registry) configure(concurrency int, qps float64) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	if qps <= 0 {
+		qps = defaultRegistryQPS
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.sem == nil || cap(r.sem) != concurrency {
+		r.sem = make(chan struct{}, concurrency)
+	}
+	if r.qps != qps {
+		r.qps = qps
+		r.limiters = map[string]*rate.Limiter{}
+	}
+}
+
+// listTags returns the tags of the repository. Tags fetched by any scan
+// within maxAge are returned from the cache, concurrent scans of the same
+// repository share one request. The key separates scans with different
+// credentials, it is empty for anonymous access.
+func (r *registry) listTags(ctx context.Context, repo name.Repository, key string, maxAge time.Duration, options ...remote.Option) ([]string, error) {
+	key = repo.String() + "#" + key
+	image := repo.String()
+
+	r.lock.Lock()
+	cached, ok := r.tags[key]
+	r.lock.Unlock()
+	if ok && time.Since(cached.fetched) < maxAge {
+		metrics.ImageScanCache.WithLabelValues(image, "hit").Inc()
+		return cached.tags, nil
+	}
+
+	result, err, shared := r.group.Do(key, func() (interface{}, error) {
+		var tags []string
+		err := r.do(ctx, repo.RegistryStr(), func() error {
+			var err error
+			tags, err = remote.List(repo, append(options, remote.WithContext(ctx))...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.store(key, tags)
+		return tags, nil
+	})
+	if shared {
+		metrics.ImageScanCache.WithLabelValues(image, "hit").Inc()
+	} else {
+		metrics.ImageScanCache.WithLabelValues(image, "miss").Inc()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.([]string), nil
+}
+
+// digest returns the digest of the image, within the limits for its
+// registry.
+func (r *registry) digest(ctx context.Context, image string, options ...remote.Option) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	var digest string
+	err = r.do(ctx, ref.Context().RegistryStr(), func() error {
+		var err error
+		digest, err = getDigest(image, append(options, remote.WithContext(ctx))...)
+		return err
+	})
+	return digest, err
+}
+
+func (r *registry) store(key string, tags []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := time.Now()
+	for k, cached := range r.tags {
+		if now.Sub(cached.fetched) > cacheExpiry {
+			delete(r.tags, k)
+		}
+	}
+	r.tags[key] = cachedTags{tags: tags, fetched: now}
+}
+
+// do runs f once the rate limit of the registry allows another request and
+// a slot of the global concurrency limit is free. A slot is only taken after
+// the rate limit, so a slow registry doesn't block the others.
+func (r *registry) do(ctx context.Context, registry string, f func() error) error {
+	r.lock.Lock()
+	sem := r.sem
+	limiter, ok := r.limiters[registry]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(r.qps), int(math.Max(1, math.Ceil(r.qps))))
+		r.limiters[registry] = limiter
+	}
+	r.lock.Unlock()
+
+	if err := limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-sem }()
+	return f()
+}
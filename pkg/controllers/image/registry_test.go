@@ -0,0 +1,104 @@
+package image
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+func TestRegistryListTagsCache(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/app/tags/list":
+			atomic.AddInt32(&requests, 1)
+			// give concurrent scans time to join the request
+			time.Sleep(50 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"name":"app","tags":["1.0.0","1.1.0"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo, err := name.NewRepository(strings.TrimPrefix(server.URL, "http://")+"/app", name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRegistry(2, 100)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tags, err := r.listTags(context.Background(), repo, "", time.Minute)
+			if err != nil {
+				t.Error(err)
+			} else if len(tags) != 2 {
+				t.Errorf("expected 2 tags, got %v", tags)
+			}
+		}()
+	}
+	wg.Wait()
+	if requests != 1 {
+		t.Errorf("expected concurrent scans to share one request, got %d", requests)
+	}
+
+	if _, err := r.listTags(context.Background(), repo, "", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the tags to be cached, got %d requests", requests)
+	}
+
+	if _, err := r.listTags(context.Background(), repo, "ns/secret", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected scans with credentials not to share the cache, got %d requests", requests)
+	}
+}
+
+func TestRegistryDoRateLimit(t *testing.T) {
+	r := newRegistry(1, 0.5)
+	noop := func() error { return nil }
+	if err := r.do(context.Background(), "slow.example.com", noop); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next request to the slow registry waits for its rate limit,
+	// without holding the only slot
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	waiting := make(chan error)
+	go func() {
+		waiting <- r.do(ctx, "slow.example.com", noop)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error)
+	go func() {
+		done <- r.do(context.Background(), "fast.example.com", noop)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a rate limited registry not to block the others")
+	}
+
+	cancel()
+	<-waiting
+}
@@ -0,0 +1,177 @@
+package image
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	gpgKeyKey     = "git.asc"
+	passphraseKey = "passphrase"
+
+	sshSigMagic     = "SSHSIG"
+	sshSigNamespace = "git"
+)
+
+// commitSigner signs commits with either a GPG or an SSH key.
+type commitSigner struct {
+	gpg *openpgp.Entity
+	ssh ssh.Signer
+}
+
+// signer reads the signing key of the commit spec, it returns nil if commits
+// aren't signed.
+func (h handler) signer(namespace string, commit v1alpha1.CommitSpec) (*commitSigner, error) {
+	if commit.SigningKey == nil {
+		return nil, nil
+	}
+	secret, err := h.secretCache.Get(namespace, commit.SigningKey.SecretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	return signerFromSecret(secret)
+}
+
+func signerFromSecret(secret *corev1.Secret) (*commitSigner, error) {
+	passphrase := secret.Data[passphraseKey]
+
+	if data, ok := secret.Data[gpgKeyKey]; ok {
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading gpg key: %w", err)
+		}
+		if len(entities) == 0 {
+			return nil, errors.New("no gpg key found")
+		}
+		entity := entities[0]
+		if entity.PrivateKey == nil {
+			return nil, errors.New("gpg key has no private key")
+		}
+		if entity.PrivateKey.Encrypted {
+			if err := entity.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, fmt.Errorf("decrypting gpg key: %w", err)
+			}
+		}
+		return &commitSigner{gpg: entity}, nil
+	}
+
+	if data, ok := secret.Data[corev1.SSHAuthPrivateKey]; ok {
+		var (
+			signer ssh.Signer
+			err    error
+		)
+		if len(passphrase) > 0 {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(data, passphrase)
+		} else {
+			signer, err = ssh.ParsePrivateKey(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading ssh key: %w", err)
+		}
+		return &commitSigner{ssh: signer}, nil
+	}
+
+	return nil, fmt.Errorf("secret %s/%s has neither %s nor %s", secret.Namespace, secret.Name, gpgKeyKey, corev1.SSHAuthPrivateKey)
+}
+
+// signSSH replaces the commit at HEAD with a copy signed by the SSH key, like
+// 'git commit -S' with gpg.format=ssh.
+func signSSH(repo *gogit.Repository, hash plumbing.Hash, signer ssh.Signer) (plumbing.Hash, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return hash, err
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(unsigned); err != nil {
+		return hash, err
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return hash, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return hash, err
+	}
+
+	commit.PGPSignature, err = sshSignature(signer, data)
+	if err != nil {
+		return hash, err
+	}
+	signed := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(signed); err != nil {
+		return hash, err
+	}
+	hash, err = repo.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return hash, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return hash, err
+	}
+	return hash, repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), hash))
+}
+
+// sshSignature returns the armored signature of the message in the format
+// of 'ssh-keygen -Y sign', see PROTOCOL.sshsig of OpenSSH.
+func sshSignature(signer ssh.Signer, message []byte) (string, error) {
+	hash := sha512.Sum512(message)
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sshSigNamespace, "", "sha512", hash[:]})...)
+
+	var (
+		sig *ssh.Signature
+		err error
+	)
+	if algorithmSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// ssh-rsa signatures use sha1, which is not accepted by git
+		sig, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, signer.PublicKey().Marshal(), sshSigNamespace, "", "sha512", ssh.Marshal(sig)})...)
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	b := &strings.Builder{}
+	b.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		b.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString("-----END SSH SIGNATURE-----\n")
+	return b.String(), nil
+}
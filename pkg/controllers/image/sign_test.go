@@ -0,0 +1,126 @@
+package image
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSignSSH(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "fleet.yaml"), []byte("defaultNamespace: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	working, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := working.Add("fleet.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := working.Commit("Update from image update automation", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "fleet", Email: "fleet@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := signSSH(repo, hash, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != signed || signed == hash {
+		t.Fatalf("expected HEAD to be the signed commit %s, got %s", signed, head.Hash())
+	}
+
+	commit, err := repo.CommitObject(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored := strings.TrimSpace(commit.PGPSignature)
+	if !strings.HasPrefix(armored, "-----BEGIN SSH SIGNATURE-----") || !strings.HasSuffix(armored, "-----END SSH SIGNATURE-----") {
+		t.Fatalf("unexpected signature %q", armored)
+	}
+	lines := strings.Split(armored, "\n")
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(lines[1:len(lines)-1], ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sig struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+	if !bytes.HasPrefix(blob, []byte(sshSigMagic)) {
+		t.Fatal("missing magic")
+	}
+	if err := ssh.Unmarshal(blob[len(sshSigMagic):], &sig); err != nil {
+		t.Fatal(err)
+	}
+	if sig.Namespace != "git" || !bytes.Equal(sig.PublicKey, signer.PublicKey().Marshal()) {
+		t.Errorf("unexpected signature header %+v", sig)
+	}
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &signature); err != nil {
+		t.Fatal(err)
+	}
+	unsigned := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(unsigned); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha512.Sum512(data)
+	var message bytes.Buffer
+	message.WriteString(sshSigMagic)
+	message.Write(ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{"git", "", "sha512", hashed[:]}))
+	if err := signer.PublicKey().Verify(message.Bytes(), &signature); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+}
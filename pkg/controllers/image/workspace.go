@@ -0,0 +1,219 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/config"
+)
+
+const defaultSyncWorkers = 2
+
+// workspaces keeps a working copy per GitRepo between image syncs, which is
+// fetched and reset to the branch of the GitRepo before each sync. Syncs of
+// the same GitRepo are serialized, syncs of different GitRepos run
+// concurrently, up to the number of workers.
+type workspaces struct {
+	dir   string
+	lock  sync.Mutex
+	repos map[string]*sync.Mutex
+	// busy counts the running syncs, free signals a released worker or a
+	// change of the number of workers
+	workers int
+	busy    int
+	free    *sync.Cond
+}
+
+func newWorkspaces(dir string, workers int) *workspaces {
+	w := &workspaces{
+		dir:   dir,
+		repos: map[string]*sync.Mutex{},
+	}
+	w.free = sync.NewCond(&w.lock)
+	w.configure(workers)
+	return w
+}
+
+// onConfig applies the number of workers of the fleet-controller config.
+func (w *workspaces) onConfig(cfg *config.Config) error {
+	w.configure(cfg.ImageSyncWorkers)
+	return nil
+}
+
+// configure changes the number of workers. Running syncs keep their worker,
+// with fewer workers new syncs wait until enough of them are released.
+func (w *workspaces) configure(workers int) {
+	if workers <= 0 {
+		workers = defaultSyncWorkers
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.workers = workers
+	w.free.Broadcast()
+}
+
+// acquire waits until no other sync of the GitRepo is running and a worker
+// is free. The returned func releases both.
+func (w *workspaces) acquire(ctx context.Context, namespace, name string) (func(), error) {
+	key := namespace + "/" + name
+
+	w.lock.Lock()
+	repoLock, ok := w.repos[key]
+	if !ok {
+		repoLock = &sync.Mutex{}
+		w.repos[key] = repoLock
+	}
+	w.lock.Unlock()
+
+	repoLock.Lock()
+
+	// wake up the wait below, if the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.lock.Lock()
+			w.free.Broadcast()
+			w.lock.Unlock()
+		case <-done:
+		}
+	}()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for w.busy >= w.workers && ctx.Err() == nil {
+		w.free.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		repoLock.Unlock()
+		return nil, err
+	}
+	w.busy++
+
+	return func() {
+		w.lock.Lock()
+		w.busy--
+		w.free.Broadcast()
+		w.lock.Unlock()
+		repoLock.Unlock()
+	}, nil
+}
+
+// path returns the directory of the working copy of the GitRepo. Namespaces
+// can't contain a slash, so directories of different GitRepos don't collide.
+func (w *workspaces) path(namespace, name string) string {
+	return filepath.Join(w.dir, namespace, name)
+}
+
+// checkout returns the working copy of the GitRepo, reset to the head of its
+// branch. The repository is cloned if there is no working copy yet, or if
+// it can't be updated.
+func (w *workspaces) checkout(ctx context.Context, gitrepo *v1alpha1.GitRepo, auth transport.AuthMethod) (string, *gogit.Repository, error) {
+	dir := w.path(gitrepo.Namespace, gitrepo.Name)
+
+	if repo, err := gogit.PlainOpen(dir); err == nil {
+		err := reset(ctx, repo, gitrepo, auth)
+		if err == nil {
+			return dir, repo, nil
+		}
+		logrus.Infof("Failed to update working copy of gitrepo %s/%s, cloning again: %v", gitrepo.Namespace, gitrepo.Name, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		return "", nil, err
+	}
+	repo, err := gogit.PlainCloneContext(ctx, dir, false, &gogit.CloneOptions{
+		URL:           gitrepo.Spec.Repo,
+		Auth:          auth,
+		RemoteName:    "origin",
+		ReferenceName: plumbing.NewBranchReferenceName(gitrepo.Spec.Branch),
+		SingleBranch:  true,
+		Depth:         1,
+		Progress:      nil,
+		Tags:          gogit.NoTags,
+	})
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return dir, repo, nil
+}
+
+// remove deletes the working copy of a deleted GitRepo.
+func (w *workspaces) remove(namespace, name string) error {
+	key := namespace + "/" + name
+
+	w.lock.Lock()
+	repoLock, ok := w.repos[key]
+	delete(w.repos, key)
+	w.lock.Unlock()
+
+	if ok {
+		repoLock.Lock()
+		defer repoLock.Unlock()
+	}
+	return os.RemoveAll(w.path(namespace, name))
+}
+
+// reset fetches the branch of the GitRepo and resets the working copy to it,
+// dropping local commits and changes of a previous sync. Working copies of
+// another repository or branch are not reused.
+func reset(ctx context.Context, repo *gogit.Repository, gitrepo *v1alpha1.GitRepo, auth transport.AuthMethod) error {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return err
+	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != gitrepo.Spec.Repo {
+		return fmt.Errorf("working copy is a clone of %v", urls)
+	}
+
+	branch := plumbing.NewBranchReferenceName(gitrepo.Spec.Branch)
+	if head, err := repo.Storer.Reference(plumbing.HEAD); err != nil {
+		return err
+	} else if head.Target() != branch {
+		return fmt.Errorf("working copy is on branch %s", head.Target())
+	}
+
+	remoteRef := plumbing.NewRemoteReferenceName("origin", gitrepo.Spec.Branch)
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", branch, remoteRef))},
+		Auth:       auth,
+		Depth:      1,
+		Force:      true,
+		Tags:       gogit.NoTags,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	ref, err := repo.Reference(remoteRef, true)
+	if err != nil {
+		return err
+	}
+
+	working, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := working.Reset(&gogit.ResetOptions{Commit: ref.Hash(), Mode: gogit.HardReset}); err != nil {
+		return err
+	}
+	return working.Clean(&gogit.CleanOptions{Dir: true})
+}
@@ -0,0 +1,192 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func commitFile(t *testing.T, repo *gogit.Repository, dir, file, content string) plumbing.Hash {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	working, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := working.Add(file); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := working.Commit("update "+file, &gogit.CommitOptions{
+		Author: &object.Signature{Name: "fleet", Email: "fleet@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestWorkspacesCheckout(t *testing.T) {
+	origin := t.TempDir()
+	upstream, err := gogit.PlainInit(origin, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, upstream, origin, "fleet.yaml", "defaultNamespace: one\n")
+	head, err := upstream.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gitrepo := &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "app"},
+		Spec:       v1alpha1.GitRepoSpec{Repo: origin, Branch: head.Name().Short()},
+	}
+	w := newWorkspaces(t.TempDir(), 1)
+
+	dir, repo, err := w.checkout(context.Background(), gitrepo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// leftovers of a failed sync
+	commitFile(t, repo, dir, "fleet.yaml", "defaultNamespace: local\n")
+	if err := os.WriteFile(filepath.Join(dir, "untracked.yaml"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	latest := commitFile(t, upstream, origin, "fleet.yaml", "defaultNamespace: two\n")
+
+	// the working copy is updated, instead of cloned again
+	if err := reset(context.Background(), repo, gitrepo, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dir2, repo, err := w.checkout(context.Background(), gitrepo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir2 != dir {
+		t.Errorf("expected the working copy to be reused, got %s and %s", dir, dir2)
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Hash() != latest {
+		t.Errorf("expected HEAD at %s, got %s", latest, ref.Hash())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "fleet.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "defaultNamespace: two\n" {
+		t.Errorf("unexpected fleet.yaml %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "untracked.yaml")); !os.IsNotExist(err) {
+		t.Error("expected untracked files to be removed")
+	}
+
+	if err := w.remove(gitrepo.Namespace, gitrepo.Name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("expected the working copy to be removed")
+	}
+}
+
+func TestWorkspacesAcquire(t *testing.T) {
+	w := newWorkspaces(t.TempDir(), 1)
+
+	release, err := w.acquire(context.Background(), "fleet-local", "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := w.acquire(ctx, "fleet-local", "two"); err == nil {
+		t.Fatal("expected to wait for a free worker")
+	}
+
+	w.configure(2)
+	acquired := make(chan func())
+	go func() {
+		release, err := w.acquire(context.Background(), "fleet-local", "one")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- release
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expected syncs of the same gitrepo to be serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("expected the gitrepo to be released")
+	}
+}
+
+func TestWorkspacesConfigure(t *testing.T) {
+	w := newWorkspaces(t.TempDir(), 2)
+
+	var releases []func()
+	for _, name := range []string{"one", "two"} {
+		release, err := w.acquire(context.Background(), "fleet-local", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+
+	// the running syncs keep their workers, after the number of workers was
+	// reduced
+	w.configure(1)
+	acquired := make(chan func())
+	go func() {
+		release, err := w.acquire(context.Background(), "fleet-local", "three")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- release
+	}()
+
+	releases[0]()
+	select {
+	case <-acquired:
+		t.Fatal("expected to wait until the running syncs fit the new number of workers")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releases[1]()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("expected a worker to be free")
+	}
+}
+
+func TestWorkspacesPath(t *testing.T) {
+	w := newWorkspaces(t.TempDir(), 1)
+	if w.path("fleet-local", "app") == w.path("fleet", "local-app") {
+		t.Error("expected different working copies for different gitrepos")
+	}
+}
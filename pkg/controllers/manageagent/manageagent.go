@@ -59,6 +59,7 @@ func Register(ctx context.Context,
 	}
 
 	namespaces.OnChange(ctx, "manage-agent", h.OnNamespace)
+	config.OnChange(ctx, h.onConfig)
 	relatedresource.WatchClusterScoped(ctx, "manage-agent-resolver", h.resolveNS, namespaces, clusters)
 	fleetcontrollers.RegisterClusterStatusHandler(ctx,
 		clusters,
@@ -74,8 +75,12 @@ func (h *handler) onClusterStatusChange(cluster *fleet.Cluster, status fleet.Clu
 	if err != nil {
 		return status, err
 	}
+	status, scheduling, err := h.reconcileAgentScheduling(cluster, status)
+	if err != nil {
+		return status, err
+	}
 	status, repo := h.reconcileAgentPrivateRepoURL(cluster, status)
-	if vars || repo {
+	if vars || scheduling || repo {
 		h.namespaces.Enqueue(cluster.Namespace)
 	}
 	return status, nil
@@ -96,13 +101,10 @@ func (h *handler) reconcileAgentEnvVars(cluster *fleet.Cluster, status fleet.Clu
 		return status, enqueue, nil
 	}
 
-	hasher := sha256.New224()
-	b, err := json.Marshal(cluster.Spec.AgentEnvVars)
+	hash, err := hashStatusField(cluster.Spec.AgentEnvVars)
 	if err != nil {
 		return status, enqueue, err
 	}
-	hasher.Write(b)
-	hash := fmt.Sprintf("%x", hasher.Sum(nil))
 
 	if status.AgentEnvVarsHash != hash {
 		// We enqueue to ensure that we edit the status after other controllers.
@@ -113,6 +115,49 @@ func (h *handler) reconcileAgentEnvVars(cluster *fleet.Cluster, status fleet.Clu
 	return status, enqueue, nil
 }
 
+// reconcileAgentScheduling checks if the tolerations, affinity, resources,
+// priority class or replicas of the agent were updated, by hashing them into
+// a status field.
+func (h *handler) reconcileAgentScheduling(cluster *fleet.Cluster, status fleet.ClusterStatus) (fleet.ClusterStatus, bool, error) {
+	spec := cluster.Spec
+	if len(spec.AgentTolerations) < 1 && spec.AgentAffinity == nil && spec.AgentResources == nil &&
+		spec.AgentPriorityClassName == "" && spec.AgentReplicas == nil {
+		if status.AgentSchedulingHash != "" {
+			status.AgentSchedulingHash = ""
+			return status, true, nil
+		}
+		return status, false, nil
+	}
+
+	hash, err := hashStatusField([]interface{}{
+		spec.AgentTolerations,
+		spec.AgentAffinity,
+		spec.AgentResources,
+		spec.AgentPriorityClassName,
+		spec.AgentReplicas,
+	})
+	if err != nil {
+		return status, false, err
+	}
+
+	if status.AgentSchedulingHash != hash {
+		status.AgentSchedulingHash = hash
+		return status, true, nil
+	}
+
+	return status, false, nil
+}
+
+func hashStatusField(field interface{}) (string, error) {
+	hasher := sha256.New224()
+	b, err := json.Marshal(field)
+	if err != nil {
+		return "", err
+	}
+	hasher.Write(b)
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
+
 func (h *handler) reconcileAgentPrivateRepoURL(cluster *fleet.Cluster, status fleet.ClusterStatus) (fleet.ClusterStatus, bool) {
 	if status.AgentPrivateRepoURL != cluster.Spec.PrivateRepoURL {
 		status.AgentPrivateRepoURL = cluster.Spec.PrivateRepoURL
@@ -121,6 +166,25 @@ func (h *handler) reconcileAgentPrivateRepoURL(cluster *fleet.Cluster, status fl
 	return status, false
 }
 
+// onConfig enqueues all namespaces containing clusters, so the agent bundles
+// pick up changes to the global agent settings.
+func (h *handler) onConfig(_ *config.Config) error {
+	clusters, err := h.clusterCache.List("", labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, cluster := range clusters {
+		if seen[cluster.Namespace] {
+			continue
+		}
+		seen[cluster.Namespace] = true
+		h.namespaces.Enqueue(cluster.Namespace)
+	}
+	return nil
+}
+
 func (h *handler) resolveNS(namespace, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
 	if cluster, ok := obj.(*fleet.Cluster); ok {
 		if _, err := h.bundleCache.Get(namespace, name.SafeConcatName(AgentBundleName, cluster.Name)); err != nil {
@@ -179,14 +243,19 @@ func (h *handler) newAgentBundle(ns string, cluster *fleet.Cluster) (runtime.Obj
 	objs := agent.Manifest(
 		agentNamespace, cluster.Spec.AgentNamespace,
 		agent.ManifestOptions{
-			AgentEnvVars:          cluster.Spec.AgentEnvVars,
-			AgentImage:            cfg.AgentImage,
-			AgentImagePullPolicy:  cfg.AgentImagePullPolicy,
-			CheckinInterval:       cfg.AgentCheckinInterval.Duration.String(),
-			Generation:            "bundle",
-			PrivateRepoURL:        cluster.Spec.PrivateRepoURL,
-			SystemDefaultRegistry: cfg.SystemDefaultRegistry,
-		},
+			AgentEnvVars:           cluster.Spec.AgentEnvVars,
+			AgentImage:             cfg.AgentImage,
+			AgentImagePullPolicy:   cfg.AgentImagePullPolicy,
+			AgentTolerations:       cluster.Spec.AgentTolerations,
+			AgentAffinity:          cluster.Spec.AgentAffinity,
+			AgentResources:         cluster.Spec.AgentResources,
+			AgentPriorityClassName: cluster.Spec.AgentPriorityClassName,
+			AgentReplicas:          cluster.Spec.AgentReplicas,
+			CheckinInterval:        cfg.AgentCheckinInterval.Duration.String(),
+			Generation:             "bundle",
+			PrivateRepoURL:         cluster.Spec.PrivateRepoURL,
+			SystemDefaultRegistry:  cfg.SystemDefaultRegistry,
+		}.WithSchedulingDefaults(cfg),
 	)
 	agentYAML, err := yaml.Export(objs...)
 	if err != nil {
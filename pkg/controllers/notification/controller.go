@@ -0,0 +1,372 @@
+// Package notification sends notifications for state changes of GitRepos and Bundles. (fleetcontroller)
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/events"
+	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/notification"
+
+	"github.com/rancher/wrangler/pkg/condition"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/pkg/kv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	gitRepoKind = "GitRepo"
+	bundleKind  = "Bundle"
+
+	// notifiedAnnotation holds the last state notifications were sent
+	// for, so state changes during a restart aren't lost
+	notifiedAnnotation = "fleet.cattle.io/notified-state"
+)
+
+// observed is the last notified state of a GitRepo or Bundle.
+type observed struct {
+	state   string
+	stalled bool
+}
+
+func (o observed) String() string {
+	if o.stalled {
+		return o.state + ",stalled"
+	}
+	return o.state
+}
+
+// lastObserved returns the state stored in the annotations. It returns
+// false, if the object wasn't observed before.
+func lastObserved(annotations map[string]string) (observed, bool) {
+	value, ok := annotations[notifiedAnnotation]
+	if !ok {
+		return observed{}, false
+	}
+	state, flags := kv.Split(value, ",")
+	return observed{state: state, stalled: flags == "stalled"}, true
+}
+
+func setObserved(obj *metav1.ObjectMeta, current observed) {
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[notifiedAnnotation] = current.String()
+}
+
+type handler struct {
+	ctx           context.Context
+	notifications fleetcontrollers.NotificationController
+	gitRepos      fleetcontrollers.GitRepoController
+	bundles       fleetcontrollers.BundleController
+	secrets       corecontrollers.SecretCache
+	sender        *notification.Sender
+}
+
+func Register(ctx context.Context,
+	notifications fleetcontrollers.NotificationController,
+	gitRepos fleetcontrollers.GitRepoController,
+	bundles fleetcontrollers.BundleController,
+	secrets corecontrollers.SecretCache) {
+	h := &handler{
+		ctx:           ctx,
+		notifications: notifications,
+		gitRepos:      gitRepos,
+		bundles:       bundles,
+		secrets:       secrets,
+		sender:        notification.NewSender(),
+	}
+
+	fleetcontrollers.RegisterNotificationStatusHandler(ctx, notifications, "Accepted", "notification", h.OnNotificationChange)
+	gitRepos.OnChange(ctx, "notification-gitrepo", h.OnGitRepoChange)
+	bundles.OnChange(ctx, "notification-bundle", h.OnBundleChange)
+}
+
+// OnNotificationChange validates the notification, the Accepted condition
+// shows the error.
+func (h *handler) OnNotificationChange(n *fleet.Notification, status fleet.NotificationStatus) (fleet.NotificationStatus, error) {
+	if err := notification.Validate(n.Spec); err != nil {
+		return status, err
+	}
+	if _, err := h.endpoint(n); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// OnGitRepoChange records the state of the GitRepo and notifies about
+// changes to it. Objects are not notified about when first seen. The state
+// is only recorded for objects selected by a notification.
+func (h *handler) OnGitRepoChange(key string, gitrepo *fleet.GitRepo) (*fleet.GitRepo, error) {
+	if gitrepo == nil {
+		return gitrepo, nil
+	}
+
+	current := observed{state: stateName(gitrepo.Status.Display.State)}
+	old, seen := lastObserved(gitrepo.Annotations)
+	if seen && old == current {
+		return gitrepo, nil
+	}
+	if !h.selected(gitrepo.Namespace, gitRepoKind, gitrepo.Labels) {
+		return gitrepo, nil
+	}
+
+	// the state is recorded before notifying, a conflict must not send the
+	// notifications twice
+	gitrepo = gitrepo.DeepCopy()
+	setObserved(&gitrepo.ObjectMeta, current)
+	gitrepo, err := h.gitRepos.Update(gitrepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if seen {
+		for _, event := range gitRepoEvents(gitrepo, old, current) {
+			h.notify(gitrepo.Namespace, gitrepo.Labels, event)
+		}
+	}
+	return gitrepo, nil
+}
+
+// OnBundleChange records the state of the bundle and notifies about changes
+// to it and stopped rollouts.
+func (h *handler) OnBundleChange(key string, bundle *fleet.Bundle) (*fleet.Bundle, error) {
+	if bundle == nil {
+		return bundle, nil
+	}
+
+	current := observed{
+		state:   stateName(bundle.Status.Display.State),
+		stalled: bundle.Status.UnavailablePartitions > bundle.Status.MaxUnavailablePartitions,
+	}
+	old, seen := lastObserved(bundle.Annotations)
+	if seen && old == current {
+		return bundle, nil
+	}
+	if !h.selected(bundle.Namespace, bundleKind, bundle.Labels) {
+		return bundle, nil
+	}
+
+	bundle = bundle.DeepCopy()
+	setObserved(&bundle.ObjectMeta, current)
+	bundle, err := h.bundles.Update(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if seen {
+		for _, event := range bundleEvents(bundle, old, current) {
+			h.notify(bundle.Namespace, bundle.Labels, event)
+		}
+	}
+	return bundle, nil
+}
+
+// gitRepoEvents returns the events for the change of the GitRepo's state.
+func gitRepoEvents(gitrepo *fleet.GitRepo, old, current observed) []notification.Event {
+	if old.state == current.state {
+		return nil
+	}
+	return []notification.Event{{
+		Kind:      gitRepoKind,
+		Namespace: gitrepo.Namespace,
+		Name:      gitrepo.Name,
+		Reason:    current.state,
+		OldState:  old.state,
+		State:     current.state,
+		Message:   gitrepo.Status.Display.Message,
+		Commit:    gitrepo.Status.Commit,
+		Time:      time.Now(),
+	}}
+}
+
+// bundleEvents returns the events for the change of the bundle's state and
+// for a rollout, which stopped because of unavailable partitions.
+func bundleEvents(bundle *fleet.Bundle, old, current observed) []notification.Event {
+	event := notification.Event{
+		Kind:      bundleKind,
+		Namespace: bundle.Namespace,
+		Name:      bundle.Name,
+		OldState:  old.state,
+		State:     current.state,
+		Commit:    bundle.Labels["fleet.cattle.io/commit"],
+		Time:      time.Now(),
+	}
+
+	var result []notification.Event
+	if old.state != current.state {
+		event.Reason = event.State
+		event.Message = bundleMessage(bundle)
+		result = append(result, event)
+	}
+	if current.stalled && !old.stalled {
+		event.Reason = events.ReasonPartitionUnavailable
+		event.Message = fmt.Sprintf("rollout paused, %d partitions are unavailable, maxUnavailablePartitions is %d",
+			bundle.Status.UnavailablePartitions, bundle.Status.MaxUnavailablePartitions)
+		result = append(result, event)
+	}
+	return result
+}
+
+// notify sends the event to all matching notifications in the namespace.
+func (h *handler) notify(namespace string, objLabels map[string]string, event notification.Event) {
+	notifications, err := h.notifications.Cache().List(namespace, labels.Everything())
+	if err != nil {
+		logrus.Errorf("failed to list notifications in %s: %v", namespace, err)
+		return
+	}
+
+	for _, n := range notifications {
+		ok, err := matches(n, objLabels, event)
+		if err != nil {
+			logrus.Errorf("notification %s/%s: %v", n.Namespace, n.Name, err)
+			continue
+		}
+		if ok {
+			go h.send(n.DeepCopy(), event)
+		}
+	}
+}
+
+// selected returns true if any notification in the namespace selects
+// objects of the kind with the labels, regardless of their events.
+func (h *handler) selected(namespace, kind string, objLabels map[string]string) bool {
+	notifications, err := h.notifications.Cache().List(namespace, labels.Everything())
+	if err != nil {
+		logrus.Errorf("failed to list notifications in %s: %v", namespace, err)
+		return false
+	}
+
+	for _, n := range notifications {
+		if ok, err := selects(n, kind, objLabels); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(n *fleet.Notification, objLabels map[string]string, event notification.Event) (bool, error) {
+	if len(n.Spec.Events) > 0 && !contains(n.Spec.Events, event.Reason) {
+		return false, nil
+	}
+	return selects(n, event.Kind, objLabels)
+}
+
+// selects returns true if the notification is active and selects objects of
+// the kind with the labels.
+func selects(n *fleet.Notification, kind string, objLabels map[string]string) (bool, error) {
+	if n.Spec.Suspend || n.DeletionTimestamp != nil {
+		return false, nil
+	}
+	if len(n.Spec.Kinds) > 0 && !contains(n.Spec.Kinds, kind) {
+		return false, nil
+	}
+	if n.Spec.Selector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(n.Spec.Selector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(objLabels)), nil
+}
+
+func (h *handler) send(n *fleet.Notification, event notification.Event) {
+	err := notification.Validate(n.Spec)
+	var endpoint notification.Endpoint
+	if err == nil {
+		endpoint, err = h.endpoint(n)
+	}
+	var payload []byte
+	if err == nil {
+		payload, err = notification.Payload(n.Spec.Provider, n.Spec.Template, event)
+	}
+	if err == nil {
+		err = h.sender.Send(h.ctx, endpoint, payload, n.Spec.Retries)
+	}
+
+	if err != nil {
+		logrus.Errorf("failed to send notification %s/%s for %s: %v", n.Namespace, n.Name, event, err)
+	} else {
+		logrus.Debugf("Sent notification %s/%s for %s", n.Namespace, n.Name, event)
+	}
+
+	if err := h.updateStatus(n.Namespace, n.Name, event, err); err != nil {
+		logrus.Errorf("failed to update status of notification %s/%s: %v", n.Namespace, n.Name, err)
+	}
+}
+
+func (h *handler) updateStatus(namespace, name string, event notification.Event, sendErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		n, err := h.notifications.Get(namespace, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		n = n.DeepCopy()
+		if sendErr != nil {
+			n.Status.Failed++
+			n.Status.LastError = sendErr.Error()
+		} else {
+			now := metav1.Now()
+			n.Status.Sent++
+			n.Status.LastError = ""
+			n.Status.LastEvent = event.String()
+			n.Status.LastSentTime = &now
+		}
+		_, err = h.notifications.UpdateStatus(n)
+		return err
+	})
+}
+
+func (h *handler) endpoint(n *fleet.Notification) (notification.Endpoint, error) {
+	if n.Spec.SecretRef == nil {
+		return notification.Endpoint{}, fmt.Errorf("secretRef is required")
+	}
+	secret, err := h.secrets.Get(n.Namespace, n.Spec.SecretRef.Name)
+	if err != nil {
+		return notification.Endpoint{}, err
+	}
+	address := string(secret.Data["address"])
+	if address == "" {
+		return notification.Endpoint{}, fmt.Errorf("secret %s/%s has no address key", secret.Namespace, secret.Name)
+	}
+	return notification.Endpoint{
+		Provider: n.Spec.Provider,
+		Address:  address,
+		Token:    string(secret.Data["token"]),
+	}, nil
+}
+
+// bundleMessage returns the message of the bundle's Ready condition.
+func bundleMessage(bundle *fleet.Bundle) string {
+	if c := condition.Cond("Ready"); c.IsFalse(bundle) {
+		return c.GetMessage(bundle)
+	}
+	return ""
+}
+
+// stateName returns the state, which is also the reason of the event. An
+// empty state is ready.
+func stateName(state string) string {
+	if state == "" {
+		return string(fleet.Ready)
+	}
+	return state
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
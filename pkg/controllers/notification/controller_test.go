@@ -0,0 +1,205 @@
+package notification
+
+import (
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/events"
+	fleetcontrollers "github.com/rancher/fleet/pkg/generated/controllers/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/notification"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type notificationController struct {
+	fleetcontrollers.NotificationController
+	notifications []*fleet.Notification
+}
+
+func (n notificationController) Cache() fleetcontrollers.NotificationCache {
+	return notificationCache{notifications: n.notifications}
+}
+
+type notificationCache struct {
+	fleetcontrollers.NotificationCache
+	notifications []*fleet.Notification
+}
+
+func (n notificationCache) List(namespace string, selector labels.Selector) (result []*fleet.Notification, err error) {
+	for _, notification := range n.notifications {
+		if notification.Namespace == namespace {
+			result = append(result, notification)
+		}
+	}
+	return result, nil
+}
+
+type gitRepoController struct {
+	fleetcontrollers.GitRepoController
+	updated []*fleet.GitRepo
+}
+
+func (g *gitRepoController) Update(gitrepo *fleet.GitRepo) (*fleet.GitRepo, error) {
+	g.updated = append(g.updated, gitrepo)
+	return gitrepo, nil
+}
+
+func TestMatches(t *testing.T) {
+	event := notification.Event{Kind: bundleKind, Reason: string(fleet.ErrApplied)}
+	objLabels := map[string]string{"env": "prod"}
+	now := metav1.Now()
+
+	tests := []struct {
+		name     string
+		spec     fleet.NotificationSpec
+		deleted  bool
+		expected bool
+	}{
+		{name: "all", expected: true},
+		{name: "suspended", spec: fleet.NotificationSpec{Suspend: true}},
+		{name: "deleted", deleted: true},
+		{name: "kind", spec: fleet.NotificationSpec{Kinds: []string{gitRepoKind, bundleKind}}, expected: true},
+		{name: "other kind", spec: fleet.NotificationSpec{Kinds: []string{gitRepoKind}}},
+		{name: "event", spec: fleet.NotificationSpec{Events: []string{string(fleet.ErrApplied)}}, expected: true},
+		{name: "other event", spec: fleet.NotificationSpec{Events: []string{string(fleet.Ready)}}},
+		{
+			name:     "selector",
+			spec:     fleet.NotificationSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+			expected: true,
+		},
+		{name: "other selector", spec: fleet.NotificationSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}}},
+	}
+	for _, test := range tests {
+		n := &fleet.Notification{Spec: test.spec}
+		if test.deleted {
+			n.DeletionTimestamp = &now
+		}
+		ok, err := matches(n, objLabels, event)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if ok != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, ok)
+		}
+	}
+
+	invalid := &fleet.Notification{Spec: fleet.NotificationSpec{Selector: &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}},
+	}}}
+	if _, err := matches(invalid, objLabels, event); err == nil {
+		t.Error("expected an error for an invalid selector")
+	}
+}
+
+func TestObserved(t *testing.T) {
+	if _, ok := lastObserved(nil); ok {
+		t.Error("expected an object without annotation not to be observed")
+	}
+
+	for _, current := range []observed{
+		{state: string(fleet.Ready)},
+		{state: string(fleet.Modified), stalled: true},
+	} {
+		obj := &metav1.ObjectMeta{}
+		setObserved(obj, current)
+		old, ok := lastObserved(obj.Annotations)
+		if !ok || old != current {
+			t.Errorf("expected %+v to be stored, got %+v", current, old)
+		}
+	}
+}
+
+func TestGitRepoEvents(t *testing.T) {
+	gitrepo := &fleet.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "repo"},
+		Status:     fleet.GitRepoStatus{Commit: "abc"},
+	}
+
+	ready := observed{state: string(fleet.Ready)}
+	if events := gitRepoEvents(gitrepo, ready, ready); len(events) != 0 {
+		t.Errorf("expected no events for an unchanged state, got %v", events)
+	}
+
+	events := gitRepoEvents(gitrepo, ready, observed{state: string(fleet.ErrApplied)})
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %v", events)
+	}
+	if e := events[0]; e.Reason != string(fleet.ErrApplied) || e.OldState != string(fleet.Ready) || e.Commit != "abc" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+func TestBundleEvents(t *testing.T) {
+	bundle := &fleet.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "repo-app"},
+		Status: fleet.BundleStatus{
+			UnavailablePartitions:    2,
+			MaxUnavailablePartitions: 1,
+		},
+	}
+
+	ready := observed{state: string(fleet.Ready)}
+	if events := bundleEvents(bundle, ready, ready); len(events) != 0 {
+		t.Errorf("expected no events for an unchanged state, got %v", events)
+	}
+
+	modified := observed{state: string(fleet.Modified), stalled: true}
+	result := bundleEvents(bundle, ready, modified)
+	if len(result) != 2 {
+		t.Fatalf("expected a state and a partition event, got %v", result)
+	}
+	if result[0].Reason != string(fleet.Modified) || result[1].Reason != events.ReasonPartitionUnavailable {
+		t.Errorf("unexpected events %v", result)
+	}
+
+	// a rollout, which is still stalled, is not notified again
+	if result := bundleEvents(bundle, modified, observed{state: string(fleet.Ready), stalled: true}); len(result) != 1 || result[0].Reason != string(fleet.Ready) {
+		t.Errorf("expected only the state event, got %v", result)
+	}
+}
+
+func TestOnGitRepoChangeSelected(t *testing.T) {
+	gitrepo := &fleet.GitRepo{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "fleet-local",
+		Name:      "repo",
+		Labels:    map[string]string{"env": "prod"},
+	}}
+	bundles := &fleet.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "bundles"},
+		Spec:       fleet.NotificationSpec{Kinds: []string{bundleKind}},
+	}
+	dev := &fleet.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "dev"},
+		Spec:       fleet.NotificationSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}},
+	}
+	other := &fleet.Notification{ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "all"}}
+
+	gitRepos := &gitRepoController{}
+	h := &handler{
+		notifications: notificationController{notifications: []*fleet.Notification{bundles, dev, other}},
+		gitRepos:      gitRepos,
+	}
+	if _, err := h.OnGitRepoChange("fleet-local/repo", gitrepo); err != nil {
+		t.Fatal(err)
+	}
+	if len(gitRepos.updated) != 0 {
+		t.Errorf("expected a gitrepo, which isn't selected by a notification, not to be updated, got %v", gitRepos.updated)
+	}
+
+	// the events of a notification don't change the selected objects
+	prod := &fleet.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "prod"},
+		Spec: fleet.NotificationSpec{
+			Events:   []string{string(fleet.ErrApplied)},
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	}
+	h.notifications = notificationController{notifications: []*fleet.Notification{bundles, dev, prod}}
+	if _, err := h.OnGitRepoChange("fleet-local/repo", gitrepo); err != nil {
+		t.Fatal(err)
+	}
+	if len(gitRepos.updated) != 1 || gitRepos.updated[0].Annotations[notifiedAnnotation] != string(fleet.Ready) {
+		t.Errorf("expected the state of the selected gitrepo to be recorded, got %v", gitRepos.updated)
+	}
+}
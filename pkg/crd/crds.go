@@ -141,6 +141,12 @@ func List() []crd.CRD {
 				WithColumn("Repository", ".spec.image").
 				WithColumn("Latest", ".status.latestTag")
 		}),
+		newCRD(&fleet.Notification{}, func(c crd.CRD) crd.CRD {
+			return c.WithCategories("fleet").
+				WithColumn("Provider", ".spec.provider").
+				WithColumn("Last-Sent", ".status.lastSentTime").
+				WithColumn("Last-Error", ".status.lastError")
+		}),
 	}
 }
 
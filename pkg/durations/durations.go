@@ -3,6 +3,9 @@ package durations
 import "time"
 
 const (
+	AgentLeaderLeaseDuration       = time.Second * 15
+	AgentLeaderRenewDeadline       = time.Second * 10
+	AgentLeaderRetryPeriod         = time.Second * 2
 	AgentRegistrationRetry         = time.Minute * 1
 	AgentSecretTimeout             = time.Minute * 1
 	DefaultClusterEnqueueDelay     = time.Second * 15
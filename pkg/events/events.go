@@ -0,0 +1,95 @@
+// Package events records Kubernetes events for state transitions of fleet
+// resources. The reasons are stable, so alerting can match on them.
+package events
+
+import (
+	"github.com/sirupsen/logrus"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events emitted by the fleet-controller and the agent.
+// Transitions of the summary state use the state name as reason, e.g.
+// "ErrApplied" or "Modified", see StateChanged.
+const (
+	// ReasonReady is used when a resource returns to the ready state.
+	ReasonReady = "Ready"
+	// ReasonPartitionUnavailable is used when a rollout stops, because
+	// more partitions than maxUnavailablePartitions are unavailable.
+	ReasonPartitionUnavailable = "PartitionUnavailable"
+	// ReasonPartitionAvailable is used when a stopped rollout continues.
+	ReasonPartitionAvailable = "PartitionAvailable"
+	// ReasonDependencyNotReady is used when bundles listed in dependsOn
+	// block a bundle deployment.
+	ReasonDependencyNotReady = "DependencyNotReady"
+	// ReasonDeployed is used when the agent installed a new deployment ID.
+	ReasonDeployed = "Deployed"
+	// ReasonDeployFailed is used when installing a deployment failed.
+	ReasonDeployFailed = "DeployFailed"
+	// ReasonDriftDetected is used when deployed resources were modified,
+	// deleted or added outside of fleet.
+	ReasonDriftDetected = "DriftDetected"
+	// ReasonDriftCorrected is used when deployed resources match the
+	// desired state again.
+	ReasonDriftCorrected = "DriftCorrected"
+)
+
+// warningStates are summary states that are reported as warnings.
+var warningStates = map[string]bool{
+	string(fleet.ErrApplied): true,
+	string(fleet.Modified):   true,
+	string(fleet.NotReady):   true,
+}
+
+// NewRecorder returns an event recorder, which sends events for core and
+// fleet resources to the API server.
+func NewRecorder(k8s kubernetes.Interface, component string) record.EventRecorder {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		logrus.Fatalf("failed to add core types to event scheme: %v", err)
+	}
+	if err := fleet.AddToScheme(scheme); err != nil {
+		logrus.Fatalf("failed to add fleet types to event scheme: %v", err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: k8s.CoreV1().Events(""),
+	})
+	return broadcaster.NewRecorder(scheme, corev1.EventSource{Component: component})
+}
+
+// StateChanged records an event if the display state of obj changed. An
+// empty state is considered ready.
+func StateChanged(recorder record.EventRecorder, obj runtime.Object, oldState, newState, message string) {
+	if recorder == nil || oldState == newState {
+		return
+	}
+	if newState == "" || newState == string(fleet.Ready) {
+		if oldState == "" || oldState == string(fleet.Ready) {
+			return
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, ReasonReady, "State changed from %s to Ready", oldState)
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if warningStates[newState] {
+		eventType = corev1.EventTypeWarning
+	}
+	if oldState == "" {
+		oldState = string(fleet.Ready)
+	}
+	if message == "" {
+		recorder.Eventf(obj, eventType, newState, "State changed from %s to %s", oldState, newState)
+		return
+	}
+	recorder.Eventf(obj, eventType, newState, "State changed from %s to %s: %s", oldState, newState, message)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"k8s.io/client-go/tools/record"
+)
+
+func TestStateChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldState string
+		newState string
+		message  string
+		expected string
+	}{
+		{name: "ready to ready", oldState: "Ready", newState: "Ready"},
+		{name: "empty to ready", oldState: "", newState: "Ready"},
+		{name: "ready to empty", oldState: "Ready", newState: ""},
+		{name: "unchanged", oldState: "WaitApplied", newState: "WaitApplied"},
+		{name: "to ready", oldState: "Modified", newState: "Ready", expected: "Normal Ready State changed from Modified to Ready"},
+		{name: "to empty", oldState: "NotReady", newState: "", expected: "Normal Ready State changed from NotReady to Ready"},
+		{name: "from empty", oldState: "", newState: "WaitApplied", expected: "Normal WaitApplied State changed from Ready to WaitApplied"},
+		{name: "err applied", oldState: "Ready", newState: "ErrApplied", expected: "Warning ErrApplied State changed from Ready to ErrApplied"},
+		{name: "modified", oldState: "Ready", newState: "Modified", message: "cm missing", expected: "Warning Modified State changed from Ready to Modified: cm missing"},
+		{name: "not ready", oldState: "Modified", newState: "NotReady", expected: "Warning NotReady State changed from Modified to NotReady"},
+		{name: "pending", oldState: "Ready", newState: "Pending", expected: "Normal Pending State changed from Ready to Pending"},
+	}
+
+	for _, test := range tests {
+		recorder := record.NewFakeRecorder(1)
+		StateChanged(recorder, &fleet.Bundle{}, test.oldState, test.newState, test.message)
+		select {
+		case event := <-recorder.Events:
+			if event != test.expected {
+				t.Errorf("%s: expected event %q, got %q", test.name, test.expected, event)
+			}
+		default:
+			if test.expected != "" {
+				t.Errorf("%s: expected event %q, got none", test.name, test.expected)
+			}
+		}
+	}
+
+	// no recorder, e.g. in the CLI
+	StateChanged(nil, &fleet.Bundle{}, "Ready", "Modified", "")
+}
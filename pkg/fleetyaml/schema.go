@@ -0,0 +1,96 @@
+package fleetyaml
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/rancher/wrangler/pkg/schemas/openapi"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const schemaID = "https://raw.githubusercontent.com/rancher/fleet/master/schemas/fleet.yaml.json"
+
+// intOrString are the fields of type intstr.IntOrString, which the openapi
+// conversion renders as plain strings.
+var intOrString = map[string]bool{
+	"maxUnavailable":           true,
+	"maxUnavailablePartitions": true,
+	"autoPartitionSize":        true,
+}
+
+// Schema returns the JSON schema of fleet.yaml, as generated from the
+// FleetYAML type. Unknown fields are not allowed, except in helm values.
+func Schema() ([]byte, error) {
+	props, err := openapi.ToOpenAPIFromStruct(FleetYAML{})
+	if err != nil {
+		return nil, err
+	}
+
+	schema := toJSONSchema("", props)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = schemaID
+	schema["title"] = "fleet.yaml"
+	schema["description"] = "Configuration of a Fleet bundle, read from fleet.yaml in a bundle directory"
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func toJSONSchema(name string, props *apiextv1.JSONSchemaProps) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	if props.Description != "" {
+		result["description"] = props.Description
+	}
+
+	if intOrString[name] || props.XIntOrString {
+		result["type"] = []string{"integer", "string", "null"}
+		return result
+	}
+
+	if props.Type != "" {
+		if props.Nullable {
+			result["type"] = []string{props.Type, "null"}
+		} else {
+			result["type"] = props.Type
+		}
+	}
+
+	if len(props.Properties) > 0 {
+		properties := map[string]interface{}{}
+		for k, v := range props.Properties {
+			v := v
+			properties[k] = toJSONSchema(k, &v)
+		}
+		result["properties"] = properties
+	}
+
+	if props.Items != nil && props.Items.Schema != nil {
+		result["items"] = toJSONSchema("", props.Items.Schema)
+	}
+
+	switch {
+	case props.AdditionalProperties != nil && props.AdditionalProperties.Schema != nil:
+		result["additionalProperties"] = toJSONSchema("", props.AdditionalProperties.Schema)
+	case props.Type == "object" && (props.XPreserveUnknownFields == nil || !*props.XPreserveUnknownFields):
+		result["additionalProperties"] = false
+	}
+
+	return result
+}
+
+// WriteSchemaFile writes the JSON schema of fleet.yaml to filename.
+func WriteSchemaFile(filename string) error {
+	data, err := Schema()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,22 @@
+package fleetyaml
+
+import (
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+)
+
+// FleetYAML is the content of a fleet.yaml file. It is the bundle spec, with
+// additional fields for the bundle's metadata, target customizations and
+// image scans.
+type FleetYAML struct {
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	fleet.BundleSpec
+	TargetCustomizations []fleet.BundleTarget `json:"targetCustomizations,omitempty"`
+	ImageScans           []ImageScan          `json:"imageScans,omitempty"`
+}
+
+// ImageScan is an image scan in a fleet.yaml file.
+type ImageScan struct {
+	Name string `json:"name,omitempty"`
+	fleet.ImageScanSpec
+}
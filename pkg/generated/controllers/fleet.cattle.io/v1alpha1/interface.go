@@ -41,6 +41,7 @@ type Interface interface {
 	GitRepo() GitRepoController
 	GitRepoRestriction() GitRepoRestrictionController
 	ImageScan() ImageScanController
+	Notification() NotificationController
 }
 
 func New(controllerFactory controller.SharedControllerFactory) Interface {
@@ -86,3 +87,7 @@ func (c *version) GitRepoRestriction() GitRepoRestrictionController {
 func (c *version) ImageScan() ImageScanController {
 	return NewImageScanController(schema.GroupVersionKind{Group: "fleet.cattle.io", Version: "v1alpha1", Kind: "ImageScan"}, "imagescans", true, c.controllerFactory)
 }
+
+func (c *version) Notification() NotificationController {
+	return NewNotificationController(schema.GroupVersionKind{Group: "fleet.cattle.io", Version: "v1alpha1", Kind: "Notification"}, "notifications", true, c.controllerFactory)
+}
@@ -19,6 +19,7 @@ import (
 	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/kustomize"
 	"github.com/rancher/fleet/pkg/manifest"
+	"github.com/rancher/fleet/pkg/metrics"
 	"github.com/rancher/fleet/pkg/rawyaml"
 	"github.com/rancher/fleet/pkg/render"
 	"github.com/rancher/wrangler/pkg/apply"
@@ -114,7 +115,7 @@ func (p *postRender) Run(renderedManifests *bytes.Buffer) (modifiedManifests *by
 		data = nil
 	}
 
-	newObjs, processed, err := kustomize.Process(p.manifest, data, p.opts.Kustomize.Dir)
+	newObjs, processed, err := kustomize.Process(p.manifest, data, p.opts.Kustomize.Dir, p.opts.Kustomize.Images)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +165,48 @@ func (p *postRender) Run(renderedManifests *bytes.Buffer) (modifiedManifests *by
 }
 
 func (h *Helm) Deploy(bundleID string, manifest *manifest.Manifest, options fleet.BundleDeploymentOptions) (*Resources, error) {
+	chart, options, err := h.chart(bundleID, manifest, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if resources, err := h.install(bundleID, manifest, chart, options, true, true); err != nil {
+		return nil, err
+	} else if h.template {
+		return releaseToResources(resources)
+	}
+
+	release, err := h.install(bundleID, manifest, chart, options, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return releaseToResources(release)
+}
+
+// DryRun installs or upgrades the release like Deploy, without changing the
+// cluster. Unlike the client only dry run Deploy starts with, it checks the
+// rendered resources against the cluster, so it fails on resources the
+// release can't adopt, e.g. without takeOwnership.
+func (h *Helm) DryRun(bundleID string, manifest *manifest.Manifest, options fleet.BundleDeploymentOptions) (*Resources, error) {
+	chart, options, err := h.chart(bundleID, manifest, options)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := h.install(bundleID, manifest, chart, options, true, false)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, fmt.Errorf("release of bundle %s is being uninstalled", bundleID)
+	}
+
+	return releaseToResources(release)
+}
+
+// chart renders the manifest into a helm chart, annotated with the bundle.
+func (h *Helm) chart(bundleID string, manifest *manifest.Manifest, options fleet.BundleDeploymentOptions) (*chart.Chart, fleet.BundleDeploymentOptions, error) {
 	if options.Helm == nil {
 		options.Helm = &fleet.HelmOptions{}
 	}
@@ -173,12 +216,12 @@ func (h *Helm) Deploy(bundleID string, manifest *manifest.Manifest, options flee
 
 	tar, err := render.HelmChart(bundleID, manifest, options)
 	if err != nil {
-		return nil, err
+		return nil, options, err
 	}
 
 	chart, err := loader.LoadArchive(tar)
 	if err != nil {
-		return nil, err
+		return nil, options, err
 	}
 
 	if chart.Metadata.Annotations == nil {
@@ -191,18 +234,7 @@ func (h *Helm) Deploy(bundleID string, manifest *manifest.Manifest, options flee
 		chart.Metadata.Annotations[CommitAnnotation] = manifest.Commit
 	}
 
-	if resources, err := h.install(bundleID, manifest, chart, options, true); err != nil {
-		return nil, err
-	} else if h.template {
-		return releaseToResources(resources)
-	}
-
-	release, err := h.install(bundleID, manifest, chart, options, false)
-	if err != nil {
-		return nil, err
-	}
-
-	return releaseToResources(release)
+	return chart, options, nil
 }
 
 func (h *Helm) mustUninstall(cfg *action.Configuration, releaseName string) (bool, error) {
@@ -282,7 +314,7 @@ func (h *Helm) getCfg(namespace, serviceAccountName string) (action.Configuratio
 	return cfg, err
 }
 
-func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *chart.Chart, options fleet.BundleDeploymentOptions, dryRun bool) (*release.Release, error) {
+func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *chart.Chart, options fleet.BundleDeploymentOptions, dryRun, clientOnly bool) (*release.Release, error) {
 	timeout, defaultNamespace, releaseName := h.getOpts(bundleID, options)
 
 	values, err := h.getValues(options, defaultNamespace)
@@ -333,7 +365,7 @@ func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *char
 
 	if install {
 		u := action.NewInstall(&cfg)
-		u.ClientOnly = h.template || dryRun
+		u.ClientOnly = h.template || clientOnly
 		u.ForceAdopt = options.Helm.TakeOwnership
 		u.Replace = true
 		u.ReleaseName = releaseName
@@ -348,7 +380,11 @@ func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *char
 		if !dryRun {
 			logrus.Infof("Helm: Installing %s", bundleID)
 		}
-		return u.Run(chart, values)
+		rel, err := u.Run(chart, values)
+		if err != nil && !dryRun && !h.template {
+			metrics.HelmFailures.WithLabelValues(bundleID, "install").Inc()
+		}
+		return rel, err
 	}
 
 	u := action.NewUpgrade(&cfg)
@@ -362,7 +398,7 @@ func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *char
 	u.Namespace = defaultNamespace
 	u.Timeout = timeout
 	u.DryRun = dryRun
-	u.DisableOpenAPIValidation = h.template || dryRun
+	u.DisableOpenAPIValidation = h.template || clientOnly
 	u.PostRenderer = pr
 	if u.Timeout > 0 {
 		u.Wait = true
@@ -370,7 +406,11 @@ func (h *Helm) install(bundleID string, manifest *manifest.Manifest, chart *char
 	if !dryRun {
 		logrus.Infof("Helm: Upgrading %s", bundleID)
 	}
-	return u.Run(releaseName, chart, values)
+	rel, err := u.Run(releaseName, chart, values)
+	if err != nil && !dryRun && !h.template {
+		metrics.HelmFailures.WithLabelValues(bundleID, "upgrade").Inc()
+	}
+	return rel, err
 }
 
 func (h *Helm) getValues(options fleet.BundleDeploymentOptions, defaultNamespace string) (map[string]interface{}, error) {
@@ -3,6 +3,7 @@ package kustomize
 import (
 	"path/filepath"
 
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
 	"github.com/rancher/fleet/pkg/content"
 	"github.com/rancher/fleet/pkg/manifest"
 
@@ -22,7 +23,10 @@ const (
 	ManifestsYAML = "fleet-manifests.yaml"
 )
 
-func Process(m *manifest.Manifest, content []byte, dir string) ([]runtime.Object, bool, error) {
+// Process runs kustomize in dir, if it has a kustomization.yaml. The content,
+// e.g. the output of helm, is added to the resources of the kustomization,
+// and the images override the images of the kustomization.
+func Process(m *manifest.Manifest, content []byte, dir string, images []fleet.KustomizeImage) ([]runtime.Object, bool, error) {
 	if dir == "" {
 		dir = "."
 	}
@@ -37,8 +41,8 @@ func Process(m *manifest.Manifest, content []byte, dir string) ([]runtime.Object
 		return nil, false, nil
 	}
 
-	if len(content) > 0 {
-		if err := modifyKustomize(fs, dir); err != nil {
+	if len(content) > 0 || len(images) > 0 {
+		if err := modifyKustomize(fs, dir, len(content) > 0, images); err != nil {
 			return nil, false, err
 		}
 	}
@@ -47,7 +51,7 @@ func Process(m *manifest.Manifest, content []byte, dir string) ([]runtime.Object
 	return objs, true, err
 }
 
-func modifyKustomize(f filesys.FileSystem, dir string) error {
+func modifyKustomize(f filesys.FileSystem, dir string, addManifests bool, images []fleet.KustomizeImage) error {
 	file := filepath.Join(dir, KustomizeYAML)
 	fileBytes, err := f.ReadFile(file)
 	if err != nil {
@@ -60,11 +64,16 @@ func modifyKustomize(f filesys.FileSystem, dir string) error {
 	}
 
 	resources := convert.ToStringSlice(data["resources"])
-	if slice.ContainsString(resources, ManifestsYAML) {
+	if addManifests && !slice.ContainsString(resources, ManifestsYAML) {
+		data["resources"] = append([]string{ManifestsYAML}, resources...)
+	} else if len(images) == 0 {
 		return nil
 	}
 
-	data["resources"] = append([]string{ManifestsYAML}, resources...)
+	if len(images) > 0 {
+		data["images"] = mergeImages(data["images"], images)
+	}
+
 	fileBytes, err = yaml.Marshal(data)
 	if err != nil {
 		return err
@@ -73,6 +82,27 @@ func modifyKustomize(f filesys.FileSystem, dir string) error {
 	return f.WriteFile(file, fileBytes)
 }
 
+// mergeImages replaces the images of a kustomization with the same name as
+// an override, and appends the others.
+func mergeImages(existing interface{}, overrides []fleet.KustomizeImage) []interface{} {
+	names := map[string]bool{}
+	for _, image := range overrides {
+		names[image.Name] = true
+	}
+
+	var result []interface{}
+	for _, image := range convert.ToInterfaceSlice(existing) {
+		if names[convert.ToString(convert.ToMapInterface(image)["name"])] {
+			continue
+		}
+		result = append(result, image)
+	}
+	for _, image := range overrides {
+		result = append(result, image)
+	}
+	return result
+}
+
 func toFilesystem(m *manifest.Manifest, dir string, manifestsContent []byte) (filesys.FileSystem, error) {
 	f := filesys.MakeEmptyDirInMemory()
 	for _, resource := range m.Resources {
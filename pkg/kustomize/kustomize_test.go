@@ -0,0 +1,56 @@
+package kustomize
+
+import (
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/manifest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestProcessImages(t *testing.T) {
+	m, err := manifest.New([]fleet.BundleResource{
+		{Name: "kustomization.yaml", Content: `resources:
+- deployment.yaml
+images:
+- name: nginx
+  newTag: "1.19"
+- name: redis
+  newTag: "6"
+`},
+		{Name: "deployment.yaml", Content: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.18
+      - name: redis
+        image: redis:5
+`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs, processed, err := Process(m, nil, "", []fleet.KustomizeImage{{Name: "nginx", NewTag: "1.21"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !processed || len(objs) != 1 {
+		t.Fatalf("expected one object, got %d", len(objs))
+	}
+
+	containers, _, _ := unstructured.NestedSlice(objs[0].(*unstructured.Unstructured).Object, "spec", "template", "spec", "containers")
+	var images []string
+	for _, c := range containers {
+		images = append(images, c.(map[string]interface{})["image"].(string))
+	}
+	if len(images) != 2 || images[0] != "nginx:1.21" || images[1] != "redis:6" {
+		t.Errorf("expected the nginx tag to be overridden, got %v", images)
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// BundleDeployDuration observes how long it takes the agent to install
+	// or upgrade the release of a bundle deployment.
+	BundleDeployDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: agentSub,
+			Name:      "bundle_deploy_duration_seconds",
+			Help:      "Duration of deploying a bundle deployment with Helm.",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		},
+		[]string{"bundle"},
+	)
+
+	// HelmFailures counts failed Helm installs and upgrades, action is
+	// either "install" or "upgrade".
+	HelmFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: agentSub,
+			Name:      "helm_failures_total",
+			Help:      "Number of failed Helm installs and upgrades.",
+		},
+		[]string{"bundle", "action"},
+	)
+
+	// DriftDetections counts how often deployed resources were found to
+	// be modified, missing or extra.
+	DriftDetections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: agentSub,
+			Name:      "drift_detections_total",
+			Help:      "Number of times the resources of a bundle deployment drifted from the desired state.",
+		},
+		[]string{"bundle"},
+	)
+
+	// TriggerWatches is the number of resource types watched for changes
+	// to deployed resources.
+	TriggerWatches = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: agentSub,
+			Name:      "trigger_watches",
+			Help:      "Number of resource types watched to trigger bundle deployment updates.",
+		},
+	)
+
+	// TriggerEvents counts watch events that triggered a bundle deployment.
+	TriggerEvents = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: agentSub,
+			Name:      "trigger_events_total",
+			Help:      "Number of watch events on deployed resources.",
+		},
+	)
+)
+
+// RegisterAgentMetrics registers the fleet-agent metrics with the default
+// registry.
+func RegisterAgentMetrics() {
+	registerOnce.Do(func() {
+		mustRegister(
+			BundleDeployDuration,
+			HelmFailures,
+			DriftDetections,
+			TriggerWatches,
+			TriggerEvents,
+		)
+		registerWorkqueueMetrics()
+	})
+}
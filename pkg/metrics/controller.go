@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// BundleState is the number of bundle deployments of a bundle in
+	// each state, from the bundle's summary.
+	BundleState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "bundle_state",
+			Help:      "Number of bundle deployments of a bundle per state.",
+		},
+		[]string{"namespace", "name", "state"},
+	)
+
+	// ClusterState is the number of bundle deployments of a cluster in
+	// each state, from the cluster's summary.
+	ClusterState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_state",
+			Help:      "Number of bundle deployments of a cluster per state.",
+		},
+		[]string{"namespace", "name", "state"},
+	)
+
+	// GitJobDuration observes the duration of finished gitjob runs,
+	// result is either "succeeded" or "failed".
+	GitJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "gitjob_duration_seconds",
+			Help:      "Duration of the jobs building bundles from a git repository.",
+			Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1200},
+		},
+		[]string{"namespace", "name", "result"},
+	)
+
+	// ClusterRegistrations counts granted cluster registration requests.
+	ClusterRegistrations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cluster_registrations_total",
+			Help:      "Number of granted cluster registrations.",
+		},
+		[]string{"namespace"},
+	)
+
+	// BundleRollouts counts bundle deployments which were updated to a new
+	// deployment ID.
+	BundleRollouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bundle_rollouts_total",
+			Help:      "Number of bundle deployments rolled out to a new deployment ID.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// ImageScanCache counts the tag lists of image repositories, which
+	// were fetched from the registry ("miss") or shared between image
+	// scans ("hit").
+	ImageScanCache = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "imagescan_cache_total",
+			Help:      "Number of image scans which fetched the tags of an image or reused the cached tags.",
+		},
+		[]string{"image", "result"},
+	)
+)
+
+// RegisterControllerMetrics registers the fleet-controller metrics with the
+// default registry.
+func RegisterControllerMetrics() {
+	registerOnce.Do(func() {
+		mustRegister(
+			BundleState,
+			ClusterState,
+			GitJobDuration,
+			ClusterRegistrations,
+			BundleRollouts,
+			ImageScanCache,
+		)
+		registerWorkqueueMetrics()
+	})
+}
+
+// SetSummaryState sets the gauge for each bundle state from the summary.
+func SetSummaryState(gauge *prometheus.GaugeVec, namespace, name string, summary fleet.BundleSummary) {
+	counts := map[fleet.BundleState]int{
+		fleet.Ready:       summary.Ready,
+		fleet.NotReady:    summary.NotReady,
+		fleet.WaitApplied: summary.WaitApplied,
+		fleet.ErrApplied:  summary.ErrApplied,
+		fleet.OutOfSync:   summary.OutOfSync,
+		fleet.Modified:    summary.Modified,
+		fleet.Pending:     summary.Pending,
+	}
+	for state, count := range counts {
+		gauge.WithLabelValues(namespace, name, string(state)).Set(float64(count))
+	}
+}
+
+// DeleteSummaryState removes the gauges of a deleted object.
+func DeleteSummaryState(gauge *prometheus.GaugeVec, namespace, name string) {
+	for state := range fleet.StateRank {
+		gauge.DeleteLabelValues(namespace, name, string(state))
+	}
+}
@@ -0,0 +1,54 @@
+// Package metrics provides the Prometheus metrics of the fleet-controller and the fleet-agent.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	namespace = "fleet"
+	agentSub  = "agent"
+)
+
+var (
+	registerOnce sync.Once
+)
+
+// Serve exposes the metrics of the default registry on /metrics until the
+// context is done. An empty address disables the endpoint.
+func Serve(ctx context.Context, addr string) {
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	go func() {
+		logrus.Infof("Serving metrics on %s/metrics", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("failed to serve metrics: %v", err)
+		}
+	}()
+}
+
+func mustRegister(collectors ...prometheus.Collector) {
+	prometheus.MustRegister(collectors...)
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestWorkqueueMetrics(t *testing.T) {
+	RegisterControllerMetrics()
+
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "fleet-test")
+	defer queue.ShutDown()
+
+	queue.Add("fleet-local/a")
+	queue.AddRateLimited("fleet-local/b")
+	if depth := testutil.ToFloat64(workqueueDepth.WithLabelValues("fleet-test")); depth != 1 {
+		t.Errorf("expected depth 1, got %v", depth)
+	}
+
+	for i := 0; i < 2; i++ {
+		item, _ := queue.Get()
+		queue.Done(item)
+	}
+
+	if adds := testutil.ToFloat64(workqueueAdds.WithLabelValues("fleet-test")); adds != 2 {
+		t.Errorf("expected 2 adds, got %v", adds)
+	}
+	if retries := testutil.ToFloat64(workqueueRetries.WithLabelValues("fleet-test")); retries != 1 {
+		t.Errorf("expected 1 retry, got %v", retries)
+	}
+	if depth := testutil.ToFloat64(workqueueDepth.WithLabelValues("fleet-test")); depth != 0 {
+		t.Errorf("expected an empty queue, got depth %v", depth)
+	}
+
+	// the histograms are exposed by the default registry
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "name" && label.GetValue() == "fleet-test" && metric.GetHistogram() != nil {
+					counts[family.GetName()] = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	for _, name := range []string{"fleet_workqueue_queue_duration_seconds", "fleet_workqueue_work_duration_seconds"} {
+		if counts[name] != 2 {
+			t.Errorf("expected 2 samples of %s, got %d", name, counts[name])
+		}
+	}
+}
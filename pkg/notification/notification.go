@@ -0,0 +1,174 @@
+// Package notification renders messages about state changes of GitRepos and
+// Bundles and sends them to Slack, Microsoft Teams or a webhook.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+)
+
+const (
+	defaultTemplate = `{{.Kind}} {{.Namespace}}/{{.Name}} changed from {{.OldState}} to {{.State}}` +
+		`{{if .Message}}: {{.Message}}{{end}}{{if .Commit}} (commit {{.Commit}}){{end}}`
+	defaultRetries = 3
+)
+
+// Event is a state change of a GitRepo or Bundle. It is the data passed
+// to the template of a notification.
+type Event struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	OldState  string    `json:"oldState"`
+	State     string    `json:"state"`
+	Message   string    `json:"message,omitempty"`
+	Commit    string    `json:"commit,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s/%s %s", e.Kind, e.Namespace, e.Name, e.Reason)
+}
+
+// Endpoint is the target of a notification, address and token are read
+// from the secret of the notification.
+type Endpoint struct {
+	Provider string
+	Address  string
+	Token    string
+}
+
+// Validate checks the provider and parses the template of a notification.
+func Validate(spec fleet.NotificationSpec) error {
+	switch spec.Provider {
+	case fleet.NotificationProviderSlack, fleet.NotificationProviderMSTeams, fleet.NotificationProviderWebhook:
+	default:
+		return fmt.Errorf("unknown provider %q, must be one of %s, %s or %s", spec.Provider,
+			fleet.NotificationProviderSlack, fleet.NotificationProviderMSTeams, fleet.NotificationProviderWebhook)
+	}
+	if spec.SecretRef == nil || spec.SecretRef.Name == "" {
+		return fmt.Errorf("secretRef is required")
+	}
+	if spec.Template != "" {
+		if _, err := template.New("notification").Parse(spec.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// Payload returns the request body for the provider. Slack and Teams
+// receive the rendered template as message text, webhooks receive the
+// rendered template as is, or the event as JSON if no template is given.
+func Payload(provider, tmpl string, event Event) ([]byte, error) {
+	if provider == fleet.NotificationProviderWebhook && tmpl == "" {
+		return json.Marshal(event)
+	}
+	if tmpl == "" {
+		tmpl = defaultTemplate
+	}
+
+	t, err := template.New("notification").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	var text bytes.Buffer
+	if err := t.Execute(&text, event); err != nil {
+		return nil, err
+	}
+
+	switch provider {
+	case fleet.NotificationProviderSlack:
+		return json.Marshal(map[string]string{
+			"text": text.String(),
+		})
+	case fleet.NotificationProviderMSTeams:
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  event.String(),
+			"text":     text.String(),
+		})
+	case fleet.NotificationProviderWebhook:
+		return text.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", provider)
+}
+
+// Sender posts payloads to endpoints.
+type Sender struct {
+	Client *http.Client
+	// Backoff is the delay before the first retry, it doubles on every
+	// further retry.
+	Backoff time.Duration
+}
+
+// NewSender returns a sender with a request timeout of 30s.
+func NewSender() *Sender {
+	return &Sender{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		Backoff: time.Second,
+	}
+}
+
+// Send posts the payload to the endpoint. Failed requests are retried,
+// unless the endpoint rejects the request with a client error.
+func (s *Sender) Send(ctx context.Context, endpoint Endpoint, payload []byte, retries *int) error {
+	attempts := defaultRetries + 1
+	if retries != nil && *retries >= 0 {
+		attempts = *retries + 1
+	}
+
+	backoff := s.Backoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = s.post(ctx, endpoint, payload)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
+
+func (s *Sender) post(ctx context.Context, endpoint Endpoint, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Address, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if endpoint.Provider == fleet.NotificationProviderWebhook && endpoint.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+endpoint.Token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("%s returned %s", endpoint.Provider, resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+)
+
+var event = Event{
+	Kind:      "GitRepo",
+	Namespace: "fleet-local",
+	Name:      "simple",
+	Reason:    "ErrApplied",
+	OldState:  "Ready",
+	State:     "ErrApplied",
+	Message:   "deployment not ready",
+}
+
+func TestPayload(t *testing.T) {
+	data, err := Payload(fleet.NotificationProviderSlack, "", event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slack map[string]string
+	if err := json.Unmarshal(data, &slack); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "GitRepo fleet-local/simple changed from Ready to ErrApplied: deployment not ready"; slack["text"] != expected {
+		t.Errorf("expected %q, got %q", expected, slack["text"])
+	}
+
+	data, err = Payload(fleet.NotificationProviderWebhook, `{"repo":"{{.Name}}"}`, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"repo":"simple"}` {
+		t.Errorf("unexpected webhook payload %s", data)
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing token, got %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("unexpected body %q", body)
+		}
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := &Sender{Client: server.Client()}
+	endpoint := Endpoint{Provider: fleet.NotificationProviderWebhook, Address: server.URL, Token: "secret"}
+
+	retries := 1
+	if err := sender.Send(context.Background(), endpoint, []byte("payload"), &retries); err == nil {
+		t.Error("expected an error after two failed attempts")
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	if err := sender.Send(context.Background(), endpoint, []byte("payload"), nil); err != nil {
+		t.Errorf("expected the third attempt to succeed, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestSendClientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := &Sender{Client: server.Client()}
+	if err := sender.Send(context.Background(), Endpoint{Provider: fleet.NotificationProviderSlack, Address: server.URL}, []byte("{}"), nil); err == nil {
+		t.Error("expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("expected client errors not to be retried, got %d requests", requests)
+	}
+}
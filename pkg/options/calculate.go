@@ -89,6 +89,7 @@ func Merge(base, next fleet.BundleDeploymentOptions) fleet.BundleDeploymentOptio
 		if next.Kustomize.Dir != "" {
 			result.Kustomize.Dir = next.Kustomize.Dir
 		}
+		result.Kustomize.Images = mergeKustomizeImages(result.Kustomize.Images, next.Kustomize.Images)
 	}
 	if next.Diff != nil {
 		if result.Diff == nil {
@@ -107,3 +108,20 @@ func Merge(base, next fleet.BundleDeploymentOptions) fleet.BundleDeploymentOptio
 	}
 	return result
 }
+
+// mergeKustomizeImages replaces the images with the same name as in next and
+// appends the others.
+func mergeKustomizeImages(base, next []fleet.KustomizeImage) []fleet.KustomizeImage {
+	result := base
+next:
+	for _, image := range next {
+		for i := range result {
+			if result[i].Name == image.Name {
+				result[i] = image
+				continue next
+			}
+		}
+		result = append(result, image)
+	}
+	return result
+}
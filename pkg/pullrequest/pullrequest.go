@@ -0,0 +1,368 @@
+// Package pullrequest opens or updates pull requests with image updates on
+// GitHub, GitLab, Gitea or Bitbucket.
+package pullrequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/update"
+)
+
+// PullRequest is a request to merge Head into Base.
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+}
+
+// Client opens pull requests in a repository through the API of a provider.
+type Client struct {
+	HTTP     *http.Client
+	Provider string
+	// URL of the API, e.g. "https://api.github.com"
+	URL string
+	// Repository is the path of the repository, e.g. "owner/repo"
+	Repository string
+	// Username is used for basic auth with the token as password, except
+	// for GitLab, which only supports tokens
+	Username string
+	Token    string
+}
+
+// Validate checks the provider of a pull request spec.
+func Validate(spec fleet.PullRequestSpec) error {
+	switch spec.Provider {
+	case fleet.PullRequestProviderGitHub, fleet.PullRequestProviderGitLab, fleet.PullRequestProviderGitea, fleet.PullRequestProviderBitbucket:
+		return nil
+	}
+	return fmt.Errorf("unknown provider %q, must be one of %s, %s, %s or %s", spec.Provider,
+		fleet.PullRequestProviderGitHub, fleet.PullRequestProviderGitLab, fleet.PullRequestProviderGitea, fleet.PullRequestProviderBitbucket)
+}
+
+// NewClient returns a client for the spec. The API URL and repository
+// default to the ones of repoURL, the git URL of the GitRepo.
+func NewClient(spec fleet.PullRequestSpec, repoURL, username, token string) (*Client, error) {
+	if err := Validate(spec); err != nil {
+		return nil, err
+	}
+	c := &Client{
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+		Provider:   spec.Provider,
+		URL:        strings.TrimSuffix(spec.URL, "/"),
+		Repository: strings.Trim(spec.Repository, "/"),
+		Username:   username,
+		Token:      token,
+	}
+	if c.URL != "" && c.Repository != "" {
+		return c, nil
+	}
+
+	host, path, err := ParseRepository(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if c.Repository == "" {
+		c.Repository = path
+	}
+	if c.URL == "" {
+		if c.URL, err = apiURL(spec.Provider, host); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ParseRepository returns the host and the path without ".git" of a git
+// URL, which is either a URL or a scp-like address like
+// "git@github.com:owner/repo.git".
+func ParseRepository(repoURL string) (string, string, error) {
+	var host, path string
+	if strings.Contains(repoURL, "://") {
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return "", "", err
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		address := repoURL
+		if i := strings.Index(address, "@"); i >= 0 {
+			address = address[i+1:]
+		}
+		var ok bool
+		if host, path, ok = strings.Cut(address, ":"); !ok {
+			return "", "", fmt.Errorf("invalid git url %q", repoURL)
+		}
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("can't determine repository of git url %q", repoURL)
+	}
+	return host, path, nil
+}
+
+func apiURL(provider, host string) (string, error) {
+	switch provider {
+	case fleet.PullRequestProviderGitHub:
+		if host == "github.com" {
+			return "https://api.github.com", nil
+		}
+		return "https://" + host + "/api/v3", nil
+	case fleet.PullRequestProviderGitLab:
+		return "https://" + host + "/api/v4", nil
+	case fleet.PullRequestProviderGitea:
+		return "https://" + host + "/api/v1", nil
+	case fleet.PullRequestProviderBitbucket:
+		if host == "bitbucket.org" {
+			return "https://api.bitbucket.org/2.0", nil
+		}
+	}
+	return "", fmt.Errorf("url is required for provider %s on %s", provider, host)
+}
+
+// Open creates the pull request, or updates title and body of the open pull
+// request from the same head to the same base. It returns the web URL of the
+// pull request.
+func (c *Client) Open(ctx context.Context, pr PullRequest) (string, error) {
+	switch c.Provider {
+	case fleet.PullRequestProviderGitHub, fleet.PullRequestProviderGitea:
+		return c.openGitHub(ctx, pr)
+	case fleet.PullRequestProviderGitLab:
+		return c.openGitLab(ctx, pr)
+	case fleet.PullRequestProviderBitbucket:
+		return c.openBitbucket(ctx, pr)
+	}
+	return "", fmt.Errorf("unknown provider %q", c.Provider)
+}
+
+type branchRef struct {
+	Ref string `json:"ref"`
+}
+
+// openGitHub is used for GitHub and Gitea, which share the pulls API.
+func (c *Client) openGitHub(ctx context.Context, pr PullRequest) (string, error) {
+	var list []struct {
+		Number  int       `json:"number"`
+		HTMLURL string    `json:"html_url"`
+		Head    branchRef `json:"head"`
+		Base    branchRef `json:"base"`
+	}
+	owner, _, _ := strings.Cut(c.Repository, "/")
+	query := url.Values{
+		"state":    {"open"},
+		"head":     {owner + ":" + pr.Head},
+		"base":     {pr.Base},
+		"per_page": {"100"},
+		"limit":    {"50"},
+	}
+	path := "/repos/" + c.Repository + "/pulls"
+	if err := c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &list); err != nil {
+		return "", err
+	}
+
+	// gitea ignores the head and base filters
+	for _, existing := range list {
+		if existing.Head.Ref == pr.Head && existing.Base.Ref == pr.Base {
+			return existing.HTMLURL, c.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", path, existing.Number), map[string]string{
+				"title": pr.Title,
+				"body":  pr.Body,
+			}, nil)
+		}
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err := c.do(ctx, http.MethodPost, path, map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}, &created)
+	return created.HTMLURL, err
+}
+
+func (c *Client) openGitLab(ctx context.Context, pr PullRequest) (string, error) {
+	type mergeRequest struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	var list []mergeRequest
+	query := url.Values{
+		"state":         {"opened"},
+		"source_branch": {pr.Head},
+		"target_branch": {pr.Base},
+	}
+	path := "/projects/" + url.QueryEscape(c.Repository) + "/merge_requests"
+	if err := c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &list); err != nil {
+		return "", err
+	}
+
+	if len(list) > 0 {
+		return list[0].WebURL, c.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", path, list[0].IID), map[string]string{
+			"title":       pr.Title,
+			"description": pr.Body,
+		}, nil)
+	}
+
+	var created mergeRequest
+	err := c.do(ctx, http.MethodPost, path, map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}, &created)
+	return created.WebURL, err
+}
+
+func (c *Client) openBitbucket(ctx context.Context, pr PullRequest) (string, error) {
+	type pullRequest struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	var list struct {
+		Values []pullRequest `json:"values"`
+	}
+	query := url.Values{
+		"state": {"OPEN"},
+		"q":     {fmt.Sprintf("source.branch.name=%q AND destination.branch.name=%q", pr.Head, pr.Base)},
+	}
+	path := "/repositories/" + c.Repository + "/pullrequests"
+	if err := c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &list); err != nil {
+		return "", err
+	}
+
+	if len(list.Values) > 0 {
+		existing := list.Values[0]
+		return existing.Links.HTML.Href, c.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", path, existing.ID), map[string]string{
+			"title":       pr.Title,
+			"description": pr.Body,
+		}, nil)
+	}
+
+	branch := func(name string) map[string]interface{} {
+		return map[string]interface{}{"branch": map[string]string{"name": name}}
+	}
+	var created pullRequest
+	err := c.do(ctx, http.MethodPost, path, map[string]interface{}{
+		"title":       pr.Title,
+		"description": pr.Body,
+		"source":      branch(pr.Head),
+		"destination": branch(pr.Base),
+	}, &created)
+	return created.Links.HTML.Href, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.Provider == fleet.PullRequestProviderGitLab:
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Token)
+	case c.Provider == fleet.PullRequestProviderGitea:
+		req.Header.Set("Authorization", "token "+c.Token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s returned %s: %s", method, req.URL.Path, c.Provider, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Body describes the image updates of a pull request, the old and new tags
+// of the images of each ImageScan.
+func Body(gitrepo string, changes []update.Change) string {
+	oldTags := map[string]map[string]bool{}
+	newTags := map[string]map[string]bool{}
+	var files []string
+	seen := map[string]bool{}
+	for _, change := range changes {
+		scan := change.ImageScan.Name
+		if oldTags[scan] == nil {
+			oldTags[scan] = map[string]bool{}
+			newTags[scan] = map[string]bool{}
+		}
+		if tag := change.OldTag(); tag != "" {
+			oldTags[scan][tag] = true
+		}
+		if tag := change.NewTag(); tag != "" {
+			newTags[scan][tag] = true
+		}
+		if !seen[change.File] {
+			seen[change.File] = true
+			files = append(files, change.File)
+		}
+	}
+
+	scans := make([]string, 0, len(oldTags))
+	for scan := range oldTags {
+		scans = append(scans, scan)
+	}
+	sort.Strings(scans)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "Image updates for GitRepo %s:\n\n", gitrepo)
+	b.WriteString("| ImageScan | Old tag | New tag |\n| --- | --- | --- |\n")
+	for _, scan := range scans {
+		fmt.Fprintf(b, "| %s | %s | %s |\n", scan, joinKeys(oldTags[scan]), joinKeys(newTags[scan]))
+	}
+	b.WriteString("\nUpdated files:\n\n")
+	for _, file := range files {
+		fmt.Fprintf(b, "- `%s`\n", file)
+	}
+	return b.String()
+}
+
+func joinKeys(m map[string]bool) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
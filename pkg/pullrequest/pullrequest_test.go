@@ -0,0 +1,123 @@
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/fleet/pkg/update"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		provider   string
+		repo       string
+		url        string
+		repository string
+	}{
+		{fleet.PullRequestProviderGitHub, "https://github.com/rancher/fleet-examples.git", "https://api.github.com", "rancher/fleet-examples"},
+		{fleet.PullRequestProviderGitHub, "git@github.example.com:org/repo.git", "https://github.example.com/api/v3", "org/repo"},
+		{fleet.PullRequestProviderGitLab, "ssh://git@gitlab.com/group/sub/project", "https://gitlab.com/api/v4", "group/sub/project"},
+		{fleet.PullRequestProviderGitea, "https://user@gitea.example.com:3000/org/repo.git", "https://gitea.example.com/api/v1", "org/repo"},
+		{fleet.PullRequestProviderBitbucket, "git@bitbucket.org:workspace/repo.git", "https://api.bitbucket.org/2.0", "workspace/repo"},
+	}
+	for _, test := range tests {
+		c, err := NewClient(fleet.PullRequestSpec{Provider: test.provider}, test.repo, "", "token")
+		if err != nil {
+			t.Errorf("%s: %v", test.repo, err)
+			continue
+		}
+		if c.URL != test.url || c.Repository != test.repository {
+			t.Errorf("%s: expected %s and %s, got %s and %s", test.repo, test.url, test.repository, c.URL, c.Repository)
+		}
+	}
+
+	if _, err := NewClient(fleet.PullRequestSpec{Provider: "svn"}, "https://github.com/a/b", "", "token"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if _, err := NewClient(fleet.PullRequestSpec{Provider: fleet.PullRequestProviderBitbucket}, "https://bitbucket.example.com/a/b", "", "token"); err == nil {
+		t.Error("expected an error for bitbucket without url")
+	}
+}
+
+func TestOpenGitHub(t *testing.T) {
+	var open []map[string]interface{}
+	var patched map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing token, got %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/repo/pulls":
+			if r.URL.Query().Get("head") != "org:image-updates" {
+				t.Errorf("unexpected head filter %q", r.URL.Query().Get("head"))
+			}
+			_ = json.NewEncoder(w).Encode(open)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/repo/pulls":
+			var pr map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&pr)
+			open = append(open, map[string]interface{}{
+				"number":   1,
+				"html_url": "https://github.com/org/repo/pull/1",
+				"head":     map[string]string{"ref": pr["head"]},
+				"base":     map[string]string{"ref": pr["base"]},
+			})
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(open[0])
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/org/repo/pulls/1":
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			_, _ = w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(fleet.PullRequestSpec{Provider: fleet.PullRequestProviderGitHub, URL: server.URL}, "https://github.com/org/repo", "", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := PullRequest{Title: "Update images", Body: "first", Head: "image-updates", Base: "main"}
+	url, err := c.Open(context.Background(), pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://github.com/org/repo/pull/1" {
+		t.Errorf("unexpected url %q", url)
+	}
+
+	pr.Body = "second"
+	if url, err = c.Open(context.Background(), pr); err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || url != "https://github.com/org/repo/pull/1" {
+		t.Errorf("expected the open pull request to be updated, got %d pull requests", len(open))
+	}
+	if patched["body"] != "second" {
+		t.Errorf("expected the body to be updated, got %v", patched)
+	}
+}
+
+func TestBody(t *testing.T) {
+	scan := types.NamespacedName{Namespace: "fleet-local", Name: "nginx"}
+	body := Body("fleet-local/simple", []update.Change{
+		{File: "deployment.yaml", Setter: "nginx", ImageScan: scan, OldValue: "nginx:1.20.0", NewValue: "nginx:1.21.0"},
+		{File: "values.yaml", Setter: "nginx:tag", ImageScan: scan, OldValue: "1.19.0", NewValue: "1.21.0"},
+		{File: "values.yaml", Setter: "nginx:name", ImageScan: scan, OldValue: "nginx", NewValue: "docker.io/nginx"},
+	})
+	for _, expected := range []string{
+		"| nginx | 1.19.0, 1.20.0 | 1.21.0 |",
+		"- `deployment.yaml`\n- `values.yaml`\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in body:\n%s", expected, body)
+		}
+	}
+}
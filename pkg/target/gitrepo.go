@@ -0,0 +1,36 @@
+package target
+
+import (
+	fleet "github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+)
+
+// GitRepoTargets returns the targets and target restrictions, which are
+// appended to the bundles of the GitRepo. The GitTargets are duplicated into
+// the restrictions, a GitRepo without targets targets the default cluster
+// group.
+func GitRepoTargets(gitrepo *fleet.GitRepo) *fleet.BundleSpec {
+	spec := &fleet.BundleSpec{}
+	for _, target := range targetsOrDefault(gitrepo.Spec.Targets) {
+		spec.Targets = append(spec.Targets, fleet.BundleTarget{
+			Name:                 target.Name,
+			ClusterName:          target.ClusterName,
+			ClusterSelector:      target.ClusterSelector,
+			ClusterGroup:         target.ClusterGroup,
+			ClusterGroupSelector: target.ClusterGroupSelector,
+		})
+		spec.TargetRestrictions = append(spec.TargetRestrictions, fleet.BundleTargetRestriction(target))
+	}
+	return spec
+}
+
+func targetsOrDefault(targets []fleet.GitTarget) []fleet.GitTarget {
+	if len(targets) == 0 {
+		return []fleet.GitTarget{
+			{
+				Name:         "default",
+				ClusterGroup: "default",
+			},
+		}
+	}
+	return targets
+}
@@ -94,6 +94,12 @@ func (m *Manager) clusterGroupsForCluster(cluster *fleet.Cluster) (result []*fle
 		return nil, err
 	}
 
+	return ClusterGroupsForCluster(cluster, cgs), nil
+}
+
+// ClusterGroupsForCluster returns the cluster groups, whose selector matches
+// the labels of the cluster (pure function)
+func ClusterGroupsForCluster(cluster *fleet.Cluster, cgs []*fleet.ClusterGroup) (result []*fleet.ClusterGroup) {
 	for _, cg := range cgs {
 		if cg.Spec.Selector == nil {
 			continue
@@ -109,7 +115,13 @@ func (m *Manager) clusterGroupsForCluster(cluster *fleet.Cluster) (result []*fle
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// ClusterGroupLabels returns the labels of the cluster groups by name, as
+// used by the bundle matcher.
+func ClusterGroupLabels(cgs []*fleet.ClusterGroup) map[string]map[string]string {
+	return clusterGroupsToLabelMap(cgs)
 }
 
 func (m *Manager) getBundlesInScopeForCluster(cluster *fleet.Cluster) ([]*fleet.Bundle, error) {
@@ -262,13 +274,7 @@ func (m *Manager) Targets(bundle *fleet.Bundle, manifest *manifest.Manifest) ([]
 				continue
 			}
 
-			opts := options.Merge(bundle.Spec.BundleDeploymentOptions, target.BundleDeploymentOptions)
-			err = addClusterLabels(&opts, cluster.Labels)
-			if err != nil {
-				return nil, err
-			}
-
-			deploymentID, err := options.DeploymentID(manifest, opts)
+			opts, deploymentID, err := DeploymentOptions(bundle, manifest, target, cluster.Labels)
 			if err != nil {
 				return nil, err
 			}
@@ -290,6 +296,19 @@ func (m *Manager) Targets(bundle *fleet.Bundle, manifest *manifest.Manifest) ([]
 	return targets, m.foldInDeployments(bundle, targets)
 }
 
+// DeploymentOptions merges the options of the bundle and the matched target,
+// adds the cluster labels to the helm values and returns them with the
+// resulting deployment ID.
+func DeploymentOptions(bundle *fleet.Bundle, manifest *manifest.Manifest, target *fleet.BundleTarget, clusterLabels map[string]string) (fleet.BundleDeploymentOptions, string, error) {
+	opts := options.Merge(bundle.Spec.BundleDeploymentOptions, target.BundleDeploymentOptions)
+	if err := addClusterLabels(&opts, clusterLabels); err != nil {
+		return opts, "", err
+	}
+
+	deploymentID, err := options.DeploymentID(manifest, opts)
+	return opts, deploymentID, err
+}
+
 func addClusterLabels(opts *fleet.BundleDeploymentOptions, labels map[string]string) (err error) {
 	clusterLabels := yaml.CleanAnnotationsForExport(labels)
 	for k, v := range labels {
@@ -464,6 +483,12 @@ func limit(count int, val ...*intstr.IntOrString) (int, error) {
 	return i, nil
 }
 
+// ValidateLimit returns an error if val is neither an int nor a percentage.
+func ValidateLimit(val *intstr.IntOrString) error {
+	_, err := limit(1, val)
+	return err
+}
+
 // MaxUnavailable returns the maximum number of unavailable deployments given the targets rollout strategy (pure function)
 func MaxUnavailable(targets []*Target) (int, error) {
 	rollout := getRollout(targets)
@@ -0,0 +1,193 @@
+package update
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// WithFields sets the fields of the scans to their latest image, without
+// setter markers. The files of the fields are relative to root, only fields
+// of files within path are updated. Like with WithSetters, the files of the
+// result are relative to path. Comments and documents of the files are
+// preserved, it's an error if a field can't be found in its file.
+func WithFields(root, path string, scans []*v1alpha1.ImageScan) (Result, error) {
+	result := Result{
+		Files: make(map[string]FileResult),
+	}
+
+	dir := filepath.Join(root, path)
+	fields := map[string][]scanField{}
+	for _, scan := range scans {
+		if len(scan.Spec.Fields) == 0 {
+			continue
+		}
+		ref, values, err := setterValues(scan)
+		if err != nil {
+			return result, err
+		}
+		if values == nil {
+			continue
+		}
+		for _, field := range scan.Spec.Fields {
+			file, err := filepath.Rel(dir, filepath.Join(root, field.File))
+			if err != nil || file == "." || strings.HasPrefix(file, "..") {
+				continue
+			}
+			setter := scan.Spec.TagName
+			if field.Value != "" {
+				setter += ":" + field.Value
+			}
+			value, ok := values[setter]
+			if !ok {
+				return result, fmt.Errorf("imagescan %s/%s has no %s value for field %s", scan.Namespace, scan.Name, field.Value, field.Path)
+			}
+			fields[file] = append(fields[file], scanField{
+				path:   field.Path,
+				setter: setter,
+				value:  value,
+				ref:    ref,
+			})
+		}
+	}
+
+	files := make([]string, 0, len(fields))
+	for file := range fields {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		changes, err := setFields(filepath.Join(dir, file), fields[file])
+		if err != nil {
+			return result, fmt.Errorf("updating %s: %w", file, err)
+		}
+		for _, change := range changes {
+			change.File = file
+			result.Changes = append(result.Changes, change.Change)
+			fileres, ok := result.Files[file]
+			if !ok {
+				fileres = FileResult{
+					Objects: make(map[ObjectIdentifier][]ImageRef),
+				}
+				result.Files[file] = fileres
+			}
+			oid := ObjectIdentifier{change.meta.GetIdentifier()}
+			if !containsRef(fileres.Objects[oid], change.ref) {
+				fileres.Objects[oid] = append(fileres.Objects[oid], change.ref)
+			}
+		}
+	}
+	return result, nil
+}
+
+// scanField is a field and the value it's set to.
+type scanField struct {
+	path   string
+	setter string
+	value  string
+	ref    imageRef
+}
+
+type fieldChange struct {
+	Change
+	ref  imageRef
+	meta yaml.ResourceMeta
+}
+
+// setFields sets the fields in all documents of the file and writes it, if
+// a value changed.
+func setFields(file string, fields []scanField) ([]fieldChange, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := (&kio.ByteReader{
+		Reader:            bytes.NewReader(data),
+		PreserveSeqIndent: true,
+	}).Read()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []fieldChange
+	for _, field := range fields {
+		found := false
+		for _, node := range nodes {
+			value, err := node.Pipe(yaml.Lookup(strings.Split(field.path, ".")...))
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				continue
+			}
+			if value.YNode().Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("field %s is not a scalar", field.path)
+			}
+			found = true
+
+			old := value.YNode().Value
+			if old == field.value {
+				continue
+			}
+			setValue(value.YNode(), field.value)
+
+			meta, _ := node.GetMeta()
+			changes = append(changes, fieldChange{
+				Change: Change{
+					Setter:    field.setter,
+					ImageScan: field.ref.policy,
+					OldValue:  old,
+					NewValue:  field.value,
+				},
+				ref:  field.ref,
+				meta: meta,
+			})
+		}
+		if !found {
+			return nil, fmt.Errorf("field %s not found", field.path)
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	out := &bytes.Buffer{}
+	if err := (kio.ByteWriter{Writer: out}).Write(nodes); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	return changes, os.WriteFile(file, out.Bytes(), info.Mode())
+}
+
+// setValue sets the value of the scalar, tags like "1.20" are quoted to
+// stay strings.
+func setValue(node *yaml.Node, value string) {
+	node.Value = value
+	node.Tag = yaml.NodeTagString
+	if node.Style == 0 && yaml.IsValueNonString(value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+}
+
+func containsRef(refs []ImageRef, ref ImageRef) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func nginxScan(fields ...v1alpha1.ImageScanField) *v1alpha1.ImageScan {
+	return &v1alpha1.ImageScan{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "nginx"},
+		Spec:       v1alpha1.ImageScanSpec{TagName: "nginx", Fields: fields},
+		Status: v1alpha1.ImageScanStatus{
+			LatestImage:  "docker.io/library/nginx:1.21",
+			LatestTag:    "1.21",
+			LatestDigest: digest,
+		},
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expected {
+		t.Errorf("unexpected %s:\n%s", filepath.Base(path), data)
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/fleet.yaml": `# deploys the app
+defaultNamespace: app
+helm:
+  chart: ./chart
+  values:
+    image:
+      # the image of the app
+      repository: docker.io/library/nginx
+      tag: 1.20 # updated by fleet
+      digest: ""
+  valuesFiles:
+    - values.yaml
+`,
+		"app/values.yaml": `# defaults
+sidecar:
+  image: docker.io/library/nginx:1.20
+---
+# overrides for production
+sidecar:
+  image: docker.io/library/nginx:1.19
+  ports:
+    - 80
+---
+other: true
+`,
+		"other/fleet.yaml": `helm:
+  values:
+    image:
+      tag: 1.20
+`,
+	})
+
+	scan := nginxScan(
+		v1alpha1.ImageScanField{File: "app/fleet.yaml", Path: "helm.values.image.tag", Value: "tag"},
+		v1alpha1.ImageScanField{File: "app/fleet.yaml", Path: "helm.values.image.digest", Value: "digest-only"},
+		v1alpha1.ImageScanField{File: "app/values.yaml", Path: "sidecar.image"},
+		v1alpha1.ImageScanField{File: "other/fleet.yaml", Path: "helm.values.image.tag", Value: "tag"},
+	)
+	result, err := WithFields(dir, "app", []*v1alpha1.ImageScan{scan})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, filepath.Join(dir, "app/fleet.yaml"), `# deploys the app
+defaultNamespace: app
+helm:
+  chart: ./chart
+  values:
+    image:
+      # the image of the app
+      repository: docker.io/library/nginx
+      tag: "1.21" # updated by fleet
+      digest: "`+digest+`"
+  valuesFiles:
+    - values.yaml
+`)
+	assertFile(t, filepath.Join(dir, "app/values.yaml"), `# defaults
+sidecar:
+  image: docker.io/library/nginx:1.21
+---
+# overrides for production
+sidecar:
+  image: docker.io/library/nginx:1.21
+  ports:
+    - 80
+---
+other: true
+`)
+	// outside of the updated path
+	assertFile(t, filepath.Join(dir, "other/fleet.yaml"), `helm:
+  values:
+    image:
+      tag: 1.20
+`)
+
+	if len(result.Changes) != 4 {
+		t.Fatalf("expected 4 changes, got %v", result.Changes)
+	}
+	change := result.Changes[0]
+	if change.File != "fleet.yaml" || change.OldTag() != "1.20" || change.NewTag() != "1.21" {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if _, ok := result.Files["values.yaml"]; !ok {
+		t.Errorf("expected values.yaml in the updated files, got %v", result.Files)
+	}
+
+	// a second run doesn't change anything
+	result, err = WithFields(dir, "app", []*v1alpha1.ImageScan{scan})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("expected no changes, got %v", result.Changes)
+	}
+}
+
+func TestWithFieldsNotFound(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"fleet.yaml": "helm:\n  values:\n    tag: 1.20\n",
+	})
+
+	scan := nginxScan(v1alpha1.ImageScanField{File: "fleet.yaml", Path: "helm.values.image.tag", Value: "tag"})
+	if _, err := WithFields(dir, "/", []*v1alpha1.ImageScan{scan}); err == nil || !strings.Contains(err.Error(), "helm.values.image.tag") {
+		t.Errorf("expected an error for the missing field, got %v", err)
+	}
+
+	scan = nginxScan(v1alpha1.ImageScanField{File: "fleet.yaml", Path: "helm.values", Value: "tag"})
+	if _, err := WithFields(dir, "/", []*v1alpha1.ImageScan{scan}); err == nil {
+		t.Error("expected an error for a field which is not a scalar")
+	}
+}
+
+func TestWithSettersHelmValues(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"fleet.yaml": `# deploys the app
+helm:
+  values:
+    image:
+      # the image of the app
+      repository: docker.io/library/nginx # {"$imagescan": "nginx:name"}
+      tag: 1.20 # {"$imagescan": "nginx:tag"}
+  valuesFiles:
+    - values.yaml
+`,
+		"values.yaml": `sidecar:
+  image: docker.io/library/nginx:1.20 # {"$imagescan": "nginx"}
+---
+# production
+sidecars:
+  - name: proxy
+    image: docker.io/library/nginx:1.20 # {"$imagescan": "nginx:digest"}
+`,
+	})
+
+	result, err := WithSetters(dir, dir, []*v1alpha1.ImageScan{nginxScan()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 3 {
+		t.Errorf("expected 3 changes, got %v", result.Changes)
+	}
+
+	assertFile(t, filepath.Join(dir, "fleet.yaml"), `# deploys the app
+helm:
+  values:
+    image:
+      # the image of the app
+      repository: docker.io/library/nginx # {"$imagescan": "nginx:name"}
+      tag: "1.21" # {"$imagescan": "nginx:tag"}
+  valuesFiles:
+    - values.yaml
+`)
+	assertFile(t, filepath.Join(dir, "values.yaml"), `sidecar:
+  image: docker.io/library/nginx:1.21 # {"$imagescan": "nginx"}
+---
+# production
+sidecars:
+  - name: proxy
+    image: docker.io/library/nginx:1.21@`+digest+` # {"$imagescan": "nginx:digest"}
+`)
+}
+
+func TestWithSettersInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"values.yaml": "image:\n  tag: 1.20 # {\"$imagescan\": \"nginx:tag\"}\n  - broken\n",
+	})
+
+	if _, err := WithSetters(dir, dir, []*v1alpha1.ImageScan{nginxScan()}); err == nil || !strings.Contains(err.Error(), "values.yaml") {
+		t.Errorf("expected an error for the invalid file, got %v", err)
+	}
+}
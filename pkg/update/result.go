@@ -1,6 +1,8 @@
 package update
 
 import (
+	"strings"
+
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -61,6 +63,60 @@ type ObjectIdentifier struct {
 // the images, regardless of object) are available via methods.
 type Result struct {
 	Files map[string]FileResult
+	// Changes are the field values replaced by the update, in the order
+	// of the files
+	Changes []Change
+}
+
+// Change is a field value replaced by a setter.
+type Change struct {
+	File      string
+	Setter    string
+	ImageScan types.NamespacedName
+	OldValue  string
+	NewValue  string
+}
+
+// OldTag returns the tag of the image before the update, or the digest for
+// setters without tag. It's empty if the setter only replaced the image name.
+func (c Change) OldTag() string {
+	return setterTag(c.Setter, c.OldValue)
+}
+
+// NewTag returns the tag of the image after the update, or the digest for
+// setters without tag.
+func (c Change) NewTag() string {
+	return setterTag(c.Setter, c.NewValue)
+}
+
+func setterTag(setter, value string) string {
+	switch {
+	case strings.HasSuffix(setter, NameSuffix):
+		return ""
+	case strings.HasSuffix(setter, TagSuffix), strings.HasSuffix(setter, DigestOnlySuffix):
+		return value
+	case strings.HasSuffix(setter, PinnedSuffix):
+		_, digest, _ := strings.Cut(value, "@")
+		return digest
+	}
+	// the image or digest setter, the digest is not part of the tag
+	image, _, _ := strings.Cut(value, "@")
+	ref, err := name.NewTag(image, name.WeakValidation)
+	if err != nil {
+		return ""
+	}
+	return ref.TagStr()
+}
+
+// Merge adds the files and changes of other to r.
+func (r *Result) Merge(other Result) {
+	if r.Files == nil {
+		r.Files = make(map[string]FileResult)
+	}
+	for file, res := range other.Files {
+		r.Files[file] = res
+	}
+	r.Changes = append(r.Changes, other.Changes...)
 }
 
 // FileResult gives the updates in a particular file.
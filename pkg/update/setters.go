@@ -2,6 +2,7 @@ package update
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 
@@ -24,6 +25,20 @@ const (
 	// setters; instead of
 	// # { "$ref": "#/definitions/
 	SetterShortHand = "$imagescan"
+
+	// The suffixes of the setter names, appended to the tag name of an
+	// ImageScan. Without a suffix, the setter writes "image:tag".
+
+	// TagSuffix marks a field for the tag
+	TagSuffix = ":tag"
+	// NameSuffix marks a field for the image name without tag
+	NameSuffix = ":name"
+	// DigestSuffix marks a field for "image:tag@sha256:..."
+	DigestSuffix = ":digest"
+	// PinnedSuffix marks a field for "image@sha256:..."
+	PinnedSuffix = ":pinned"
+	// DigestOnlySuffix marks a field for the digest "sha256:..."
+	DigestOnlySuffix = ":digest-only"
 )
 
 func init() {
@@ -32,8 +47,9 @@ func init() {
 
 // WithSetters takes all YAML files from `inpath`, updates any
 // that contain an "in scope" image policy marker, and writes files it
-// updated (and only those files) back to `outpath`.
-func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) error {
+// updated (and only those files) back to `outpath`. The result lists
+// the updated objects and the replaced field values.
+func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) (Result, error) {
 	var settersSchema spec.Schema
 
 	// collect setter defs and setters by going through all the image
@@ -76,11 +92,18 @@ func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) error {
 	// used to separate namespace and name in the key, because a slash
 	// would be interpreted as part of the $ref path.
 	imageRefs := make(map[string]imageRef)
-	setAllCallback := func(file, setterName string, node *yaml.RNode) {
+	setAllCallback := func(file, setterName, oldValue, newValue string, node *yaml.RNode) {
 		ref, ok := imageRefs[setterName]
 		if !ok {
 			return
 		}
+		result.Changes = append(result.Changes, Change{
+			File:      file,
+			Setter:    setterName,
+			ImageScan: ref.policy,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+		})
 
 		meta, err := node.GetMeta()
 		if err != nil {
@@ -107,48 +130,14 @@ func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) error {
 
 	defs := map[string]spec.Schema{}
 	for _, scan := range scans {
-		if scan.Status.LatestImage == "" {
-			continue
-		}
-		// Using strict validation would mean any image that omits the
-		// registry would be rejected, so that can't be used
-		// here. Using _weak_ validation means that defaults will be
-		// filled in. Usually this would mean the tag would end up
-		// being `latest` if empty in the input; but I'm assuming here
-		// that the policy won't have a tagless ref.
-		image := scan.Status.LatestImage
-		r, err := name.ParseReference(image, name.WeakValidation)
+		ref, values, err := setterValues(scan)
 		if err != nil {
-			return fmt.Errorf("encountered invalid image ref %q: %w", scan.Status.LatestImage, err)
+			return result, err
 		}
-		ref := imageRef{
-			Reference: r,
-			policy: types.NamespacedName{
-				Name:      scan.Name,
-				Namespace: scan.Namespace,
-			},
+		for setter, value := range values {
+			defs[fieldmeta.SetterDefinitionPrefix+setter] = setterSchema(setter, value)
+			imageRefs[setter] = ref
 		}
-		tag := ref.Identifier()
-		// annoyingly, neither the library imported above, nor an
-		// alternative, I found will yield the original image name;
-		// this is an easy way to get it
-		name := image[:len(image)-len(tag)-1]
-
-		imageSetter := scan.Spec.TagName
-		defs[fieldmeta.SetterDefinitionPrefix+imageSetter] = setterSchema(imageSetter, scan.Status.LatestImage)
-		imageRefs[imageSetter] = ref
-
-		tagSetter := imageSetter + ":tag"
-		defs[fieldmeta.SetterDefinitionPrefix+tagSetter] = setterSchema(tagSetter, tag)
-		imageRefs[tagSetter] = ref
-
-		// Context().Name() gives the image repository _as supplied_
-		nameSetter := imageSetter + ":name"
-		defs[fieldmeta.SetterDefinitionPrefix+nameSetter] = setterSchema(nameSetter, name)
-		imageRefs[nameSetter] = ref
-
-		digestSetter := imageSetter + ":digest"
-		defs[fieldmeta.SetterDefinitionPrefix+digestSetter] = setterSchema(digestSetter, fmt.Sprintf("%s@%s", scan.Status.LatestImage, scan.Status.LatestDigest))
 	}
 
 	settersSchema.Definitions = defs
@@ -173,7 +162,15 @@ func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) error {
 		},
 	}
 
-	return pipeline.Execute()
+	if err := pipeline.Execute(); err != nil {
+		return result, err
+	}
+	// files with markers, which are not valid YAML, would silently keep
+	// the old image
+	if len(reader.ProblemFiles) > 0 {
+		return result, fmt.Errorf("failed to parse files with %s markers: %s", SetterShortHand, strings.Join(reader.ProblemFiles, ", "))
+	}
+	return result, nil
 }
 
 // setAll returns a kio.Filter using the supplied SetAllCallback
@@ -182,7 +179,7 @@ func WithSetters(inpath, outpath string, scans []*v1alpha1.ImageScan) error {
 // files with changed nodes. This is based on
 // [`SetAll`](https://github.com/kubernetes-sigs/kustomize/blob/kyaml/v0.10.16/kyaml/setters2/set.go#L503
 // from kyaml/kio.
-func setAll(filter *SetAllCallback, callback func(file, setterName string, node *yaml.RNode)) kio.Filter {
+func setAll(filter *SetAllCallback, callback func(file, setterName, oldValue, newValue string, node *yaml.RNode)) kio.Filter {
 	return kio.FilterFunc(
 		func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 			filesToUpdate := sets.String{}
@@ -194,7 +191,7 @@ func setAll(filter *SetAllCallback, callback func(file, setterName string, node
 
 				filter.Callback = func(setter, oldValue, newValue string) {
 					if newValue != oldValue {
-						callback(path, setter, nodes[i])
+						callback(path, setter, oldValue, newValue, nodes[i])
 						filesToUpdate.Insert(path)
 					}
 				}
@@ -218,6 +215,57 @@ func setAll(filter *SetAllCallback, callback func(file, setterName string, node
 		})
 }
 
+// setterValues returns the values of the setters of the scan's latest image,
+// by setter name. It returns no setters if the scan didn't find an image yet.
+func setterValues(scan *v1alpha1.ImageScan) (imageRef, map[string]string, error) {
+	if scan.Status.LatestImage == "" {
+		return imageRef{}, nil, nil
+	}
+	// Using strict validation would mean any image that omits the
+	// registry would be rejected, so that can't be used
+	// here. Using _weak_ validation means that defaults will be
+	// filled in. Usually this would mean the tag would end up
+	// being `latest` if empty in the input; but I'm assuming here
+	// that the policy won't have a tagless ref.
+	image := scan.Status.LatestImage
+	r, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return imageRef{}, nil, fmt.Errorf("encountered invalid image ref %q: %w", scan.Status.LatestImage, err)
+	}
+	ref := imageRef{
+		Reference: r,
+		policy: types.NamespacedName{
+			Name:      scan.Name,
+			Namespace: scan.Namespace,
+		},
+	}
+	tag := ref.Identifier()
+	// annoyingly, neither the library imported above, nor an
+	// alternative, I found will yield the original image name;
+	// this is an easy way to get it
+	name := image[:len(image)-len(tag)-1]
+
+	imageSetter := scan.Spec.TagName
+	values := map[string]string{
+		imageSetter:             scan.Status.LatestImage,
+		imageSetter + TagSuffix: tag,
+		// Context().Name() gives the image repository _as supplied_
+		imageSetter + NameSuffix: name,
+	}
+
+	if scan.Status.LatestDigest == "" {
+		return ref, values, nil
+	}
+
+	// image:tag@sha256:...
+	values[imageSetter+DigestSuffix] = fmt.Sprintf("%s@%s", scan.Status.LatestImage, scan.Status.LatestDigest)
+	// image@sha256:..., pinned to the digest without the tag
+	values[imageSetter+PinnedSuffix] = fmt.Sprintf("%s@%s", name, scan.Status.LatestDigest)
+	// sha256:..., e.g. for a separate digest field in helm values
+	values[imageSetter+DigestOnlySuffix] = scan.Status.LatestDigest
+	return ref, values, nil
+}
+
 func setterSchema(name, value string) spec.Schema {
 	schema := spec.StringProperty()
 	schema.Extensions = map[string]interface{}{}
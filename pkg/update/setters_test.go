@@ -0,0 +1,91 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const digest = "sha256:4b1b6f8d2b4b7a10ef3e0fe8d2bd9fa0d3f23ae1f0b56df1e2d7f2b7f0c3e7a1"
+
+func TestWithSettersDigest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: tag
+        image: docker.io/library/nginx:1.20 # {"$imagescan": "nginx"}
+      - name: digest
+        image: docker.io/library/nginx:1.20 # {"$imagescan": "nginx:digest"}
+      - name: pinned
+        image: docker.io/library/nginx:1.20 # {"$imagescan": "nginx:pinned"}
+      - name: digest-only
+        image: sha256:0 # {"$imagescan": "nginx:digest-only"}
+`
+	if err := os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := &v1alpha1.ImageScan{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-local", Name: "nginx"},
+		Spec:       v1alpha1.ImageScanSpec{TagName: "nginx"},
+		Status: v1alpha1.ImageScanStatus{
+			LatestImage:  "docker.io/library/nginx:1.21",
+			LatestTag:    "1.21",
+			LatestDigest: digest,
+		},
+	}
+	result, err := WithSetters(dir, dir, []*v1alpha1.ImageScan{scan})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "deployment.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: tag
+        image: docker.io/library/nginx:1.21 # {"$imagescan": "nginx"}
+      - name: digest
+        image: docker.io/library/nginx:1.21@` + digest + ` # {"$imagescan": "nginx:digest"}
+      - name: pinned
+        image: docker.io/library/nginx@` + digest + ` # {"$imagescan": "nginx:pinned"}
+      - name: digest-only
+        image: ` + digest + ` # {"$imagescan": "nginx:digest-only"}
+`
+	if string(data) != expected {
+		t.Errorf("unexpected manifest:\n%s", data)
+	}
+
+	if len(result.Changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(result.Changes))
+	}
+	for _, change := range result.Changes {
+		if change.ImageScan.Name != "nginx" || change.File != "deployment.yaml" {
+			t.Errorf("unexpected change %+v", change)
+		}
+	}
+	if tag := result.Changes[0].OldTag(); tag != "1.20" {
+		t.Errorf("expected old tag 1.20, got %s", tag)
+	}
+	if tag := result.Changes[2].NewTag(); tag != digest {
+		t.Errorf("expected the digest as new tag of the pinned setter, got %s", tag)
+	}
+}